@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"legiskuy-backend/pkg/config"
+	"log"
+	"os"
+)
+
+const configUsage = `Usage: legiskuy config print [flags]
+
+Prints the effective configuration as YAML, with secrets redacted. The flags
+are the ones the server takes.
+
+Flags:
+`
+
+// loadConfig loads the configuration with the flags of fs and stops the
+// program if it is invalid.
+func loadConfig(fs *flag.FlagSet, args []string) *config.Config {
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		log.Fatal("Gagal memuat konfigurasi: ", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	return cfg
+}
+
+// runConfig handles "config" on the command line. The configuration is
+// printed even when it is invalid, followed by what is wrong with it.
+func runConfig(args []string) {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, configUsage)
+		fs.PrintDefaults()
+	}
+	command := ""
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if command != "print" || fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(out)
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
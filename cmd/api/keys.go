@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"legiskuy-backend/pkg/jwtkeys"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const keysUsage = `Usage: legiskuy keys generate -dir <directory> [flags]
+
+Commands:
+  generate   write a new private key for signing access tokens to
+             <directory>/<id>.pem
+
+Flags:
+`
+
+// runKeys handles "keys" on the command line. A new key only verifies tokens
+// until the signing key ID names it, so it can be deployed to every server
+// before any of them signs with it.
+func runKeys(args []string) {
+	fs := flag.NewFlagSet("keys", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, keysUsage)
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 || args[0] != "generate" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	dir := fs.String("dir", "", "directory to write the key to")
+	algorithm := fs.String("alg", jwtkeys.EdDSA, "algorithm of the key, EdDSA or RS256")
+	id := fs.String("id", time.Now().UTC().Format("20060102-150405"), "ID of the key, used as the kid of the tokens it signs")
+	if err := fs.Parse(args[1:]); err != nil || fs.NArg() > 0 || *dir == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	data, err := jwtkeys.Generate(*algorithm)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		log.Fatal(err)
+	}
+	path := filepath.Join(*dir, *id+".pem")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		log.Fatalf("%s already exists", path)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		log.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Wrote %s key %s to %s\n", *algorithm, *id, path)
+}
@@ -1,22 +1,17 @@
 package main
 
 import (
+	"flag"
+	"legiskuy-backend/internal/app"
 	"legiskuy-backend/internal/auth"
-	"legiskuy-backend/internal/candidate"
-	"legiskuy-backend/internal/election"
-	"legiskuy-backend/internal/voter"
+	"legiskuy-backend/internal/user"
 	"legiskuy-backend/pkg/database"
-	"legiskuy-backend/pkg/middleware"
+	"legiskuy-backend/pkg/jwtkeys"
 	"log"
 	"os"
+	"strconv"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/joho/godotenv"
-
-	_ "legiskuy-backend/docs"
-
-	"github.com/gofiber/swagger"
 )
 
 // @title LegisKuy API
@@ -37,62 +32,73 @@ func main() {
 		log.Println("Warning: .env file not found, using environment variables")
 	}
 
-	database.ConnectDB()
-
-	app := fiber.New()
-
-	app.Use(logger.New())
-
-	api := app.Group("/api")
-	v1 := api.Group("/v1")
-
-	voterRepo := voter.NewRepository()
-	authRepo := auth.NewRepository()
-	authService := auth.NewService(authRepo, voterRepo)
-	authHandler := auth.NewHandler(authService)
-	v1.Post("/register", authHandler.Register)
-	v1.Post("/login", authHandler.Login)
-
-	protected := v1.Group("/", middleware.Protected())
-
-	candidateRepo := candidate.NewRepository()
-	candidateService := candidate.NewService(candidateRepo)
-	candidateHandler := candidate.NewHandler(candidateService)
-
-	petugasOnly := protected.Group("/", middleware.RequireRole("petugas"))
-
-	petugasOnly.Post("/candidates", candidateHandler.CreateCandidate)
-	petugasOnly.Put("/candidates/:id", candidateHandler.UpdateCandidate)
-	petugasOnly.Delete("/candidates/:id", candidateHandler.DeleteCandidate)
-
-	voterService := voter.NewService(voterRepo)
-	voterHandler := voter.NewHandler(voterService)
-
-	petugasOnly.Post("/voters", voterHandler.CreateVoter)
-	petugasOnly.Put("/voters/:id", voterHandler.UpdateVoter)
-	petugasOnly.Delete("/voters/:id", voterHandler.DeleteVoter)
-
-	electionRepo := election.NewRepository()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "config":
+			runConfig(os.Args[2:])
+			return
+		case "users":
+			runUsers(os.Args[2:])
+			return
+		case "keys":
+			runKeys(os.Args[2:])
+			return
+		}
+	}
 
-	electionService := election.NewService(electionRepo, voterRepo, candidateRepo)
-	electionHandler := election.NewHandler(electionService)
+	fs := flag.NewFlagSet("legiskuy", flag.ExitOnError)
+	cfg := loadConfig(fs, os.Args[1:])
+	if fs.NArg() > 0 {
+		log.Fatalf("unknown command %q", fs.Arg(0))
+	}
 
-	petugasOnly.Post("/election/time", electionHandler.SetElectionTime)
-	petugasOnly.Post("/election/threshold", electionHandler.SetThreshold)
+	var keys *jwtkeys.KeySet
+	if cfg.Auth.KeysDir == "" {
+		keys, err = jwtkeys.Ephemeral()
+		if err != nil {
+			log.Fatal("Gagal membuat kunci JWT:", err)
+		}
+		log.Println("Peringatan: direktori kunci JWT tidak diatur, memakai kunci acak. Token tidak berlaku lagi setelah aplikasi dimulai ulang.")
+	} else {
+		keys, err = jwtkeys.LoadDir(cfg.Auth.KeysDir, cfg.Auth.SigningKeyID)
+		if err != nil {
+			log.Fatal("Gagal memuat kunci JWT: ", err)
+		}
+	}
+	log.Printf("Token ditandatangani dengan kunci %s.", keys.SigningKeyID())
 
-	protected.Get("/candidates", candidateHandler.GetAllCandidates)
-	protected.Get("/candidates/:id", candidateHandler.GetCandidateByID)
-	protected.Get("/voters", voterHandler.GetAllVoters)
-	protected.Get("/voters/:id", voterHandler.GetVoterByID)
-	protected.Get("/results", electionHandler.GetResults)
-	protected.Post("/votes", electionHandler.CastVote)
+	db, err := database.Connect(cfg.Database.Driver, cfg.Database.URL)
+	if err != nil {
+		log.Fatal("Gagal terhubung ke database:", err)
+	}
+	log.Printf("Berhasil terhubung ke database (%s).", db.Dialect.Name())
 
-	app.Get("/swagger/*", swagger.HandlerDefault)
+	if _, err := database.Migrate(db, 0); err != nil {
+		log.Fatal("Gagal menjalankan migrasi database:", err)
+	}
+	log.Println("Skema database sudah terbaru.")
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
+	admins, err := user.NewRepository(db).CountActiveAdmins()
+	if err != nil {
+		log.Fatal("Gagal memeriksa akun admin:", err)
+	}
+	if admins == 0 {
+		log.Println("Peringatan: belum ada akun aktif yang dapat mengelola pengguna. Buat akun pertama dengan perintah: legiskuy users create -username <username>")
 	}
 
-	log.Fatal(app.Listen(":" + port))
+	server := app.New(app.Config{
+		DB:              db,
+		Keys:            keys,
+		AccessTokenTTL:  cfg.Auth.AccessTokenTTL,
+		RefreshTokenTTL: cfg.Auth.RefreshTokenTTL,
+		TwoFactor:       auth.TwoFactorPolicy{RequireForStaff: cfg.Auth.RequireTwoFactor},
+		ProxyHeader:     cfg.Server.ProxyHeader,
+		TrustedProxies:  cfg.Server.TrustedProxies,
+		Logger:          cfg.Server.LogRequests,
+	})
+
+	log.Fatal(server.Listen(":" + strconv.Itoa(cfg.Server.Port)))
 }
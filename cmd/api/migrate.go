@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"legiskuy-backend/pkg/database"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+const migrateUsage = `Usage: legiskuy migrate [flags] <command> [n]
+
+Commands:
+  up [n]     apply all pending migrations, or only the next n
+  down [n]   roll back the last n applied migrations (default 1)
+  status     list every migration and when it was applied
+  unlock     release the schema lock left by an interrupted run
+
+Flags:
+`
+
+// runMigrate handles "migrate" on the command line. The schema is opened
+// without the automatic migration the server runs on startup.
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		fs.PrintDefaults()
+	}
+	cfg := loadConfig(fs, args)
+	if fs.NArg() == 0 || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	command := fs.Arg(0)
+	n := 0
+	if command == "down" {
+		n = 1
+	}
+	if fs.NArg() == 2 {
+		var err error
+		if n, err = strconv.Atoi(fs.Arg(1)); err != nil || n <= 0 {
+			log.Fatalf("invalid number of migrations %q", fs.Arg(1))
+		}
+	}
+
+	db, err := database.Connect(cfg.Database.Driver, cfg.Database.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	switch command {
+	case "up":
+		applied, err := database.Migrate(db, n)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d migration(s) applied\n", len(applied))
+	case "down":
+		reverted, err := database.Rollback(db, n)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d migration(s) rolled back\n", len(reverted))
+	case "status":
+		statuses, err := database.MigrationStatuses(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+	case "unlock":
+		if err := database.Unlock(db); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("schema lock released")
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
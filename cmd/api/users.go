@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"legiskuy-backend/internal/auth"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/internal/user"
+	"legiskuy-backend/pkg/config"
+	"legiskuy-backend/pkg/database"
+	"log"
+	"os"
+	"strings"
+)
+
+const usersUsage = `Usage: legiskuy users <command> -username <username> [flags]
+
+Commands:
+  create     create a staff account, super_admin unless -role says
+             otherwise, reading its password from the first line of
+             standard input
+  reset-2fa  turn off the two-factor authentication of an account whose
+             owner lost both their authenticator app and recovery codes
+
+Flags:
+`
+
+// runUsers handles "users" on the command line. It is how the first account
+// is made, since only users who can manage users can create others through
+// the API, and how an account is let in again that nobody can log in to.
+func runUsers(args []string) {
+	fs := flag.NewFlagSet("users", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usersUsage)
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "create":
+		createUser(fs, args[1:])
+	case "reset-2fa":
+		resetTwoFactor(fs, args[1:])
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+func createUser(fs *flag.FlagSet, args []string) {
+	username := fs.String("username", "", "username of the account")
+	name := fs.String("name", "", "name of the account holder (default the username)")
+	role := fs.String("role", rbac.RoleSuperAdmin, "role of the account")
+	cfg := loadConfig(fs, args)
+	if fs.NArg() > 0 || *username == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *name == "" {
+		*name = *username
+	}
+
+	password, err := readPassword(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := openDatabase(cfg)
+	defer db.Close()
+
+	// Whoever runs the command may grant any role, as super_admin may.
+	roleRepo := rbac.NewRepository(db)
+	grantor, err := roleRepo.FindPermissions(rbac.RoleSuperAdmin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	service := user.NewService(user.NewRepository(db), roleRepo)
+	created, err := service.CreateUser(grantor, &user.CreateUserInput{
+		Name:     *name,
+		Username: *username,
+		Password: password,
+		Role:     *role,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Created %s %s (id %d)\n", created.Role, created.Username, created.ID)
+}
+
+// resetTwoFactor turns off two-factor authentication of an account. Its
+// owner can then log in with their password and turn it on again.
+func resetTwoFactor(fs *flag.FlagSet, args []string) {
+	username := fs.String("username", "", "username of the account")
+	cfg := loadConfig(fs, args)
+	if fs.NArg() > 0 || *username == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	db := openDatabase(cfg)
+	defer db.Close()
+
+	repo := auth.NewRepository(db)
+	account, err := repo.FindByUsername(*username)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if account == nil {
+		log.Fatalf("there is no account %s", *username)
+	}
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tx.Rollback()
+	if err := repo.DeleteTOTP(tx, account.ID); err != nil {
+		log.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Turned off two-factor authentication of %s (id %d)\n", account.Username, account.ID)
+}
+
+// openDatabase connects to the database of cfg and brings its schema up to
+// date.
+func openDatabase(cfg *config.Config) *database.Database {
+	db, err := database.Connect(cfg.Database.Driver, cfg.Database.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := database.Migrate(db, 0); err != nil {
+		db.Close()
+		log.Fatal(err)
+	}
+	return db
+}
+
+// readPassword reads the first line of r. A prompt is shown, but the
+// password is echoed if typed, so piping it in is better:
+//
+//	printf '%s\n' "$PASSWORD" | legiskuy users create -username admin
+func readPassword(r io.Reader) (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("cannot read password: %w", err)
+	}
+	fmt.Fprintln(os.Stderr)
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,243 @@
+// Command verify-ledger replays the ballot ledger of an election and checks it
+// against the Merkle root published when the election closed and the
+// candidate tallies.
+//
+// The root is given with -root, as it was published outside the server: the
+// root stored next to the ledger proves nothing, since whoever can rewrite
+// the ledger can rewrite it too.
+//
+// It reads either an export from GET /api/v1/elections/{id}/ledger/entries:
+//
+//	verify-ledger -root <root> -file ledger.json
+//
+// or a copy of the database file, opened read-only:
+//
+//	verify-ledger -root <root> -db legiskuy.db -election 1
+//
+// or a PostgreSQL database, through a connection string:
+//
+//	verify-ledger -root <root> -driver postgres -db postgres://user@host/legiskuy -election 1
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/ledger"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type tally struct {
+	CandidateID int `json:"candidate_id"`
+	Votes       int `json:"votes"`
+}
+
+type export struct {
+	ElectionID int            `json:"election_id"`
+	MerkleRoot *string        `json:"merkle_root"`
+	Tallies    []tally        `json:"tallies"`
+	Entries    []ledger.Entry `json:"entries"`
+
+	// problems found while reading the database, before the replay.
+	problems []string
+}
+
+func main() {
+	file := flag.String("file", "", "ledger export (JSON) to verify")
+	dbPath := flag.String("db", "", "database file, or connection string with -driver postgres, to verify")
+	driver := flag.String("driver", "sqlite", "database driver: sqlite or postgres")
+	electionID := flag.Int("election", 0, "election to verify (with -db)")
+	root := flag.String("root", "", "Merkle root published when the election closed (required)")
+	flag.Parse()
+	if *root == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var exports []export
+	var err error
+	switch {
+	case *file != "":
+		exports, err = readFile(*file)
+	case *dbPath != "" && *electionID != 0:
+		exports, err = readDatabase(*driver, *dbPath, *electionID)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := false
+	for _, e := range exports {
+		problems := verify(e, *root)
+		if len(problems) == 0 {
+			fmt.Printf("election %d: OK (%d entries)\n", e.ElectionID, len(e.Entries))
+			continue
+		}
+		failed = true
+		fmt.Printf("election %d: FAILED\n", e.ElectionID)
+		for _, p := range problems {
+			fmt.Printf("  - %s\n", p)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// verify replays the chain, rebuilds the Merkle root and compares it with the
+// published one, and recounts the ballots per candidate, returning every
+// problem found.
+func verify(e export, publishedRoot string) []string {
+	problems := e.problems
+
+	if err := ledger.VerifyChain(e.Entries); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	hashes := make([]string, 0, len(e.Entries))
+	counts := make(map[int]int)
+	for _, entry := range e.Entries {
+		if entry.ElectionID != e.ElectionID {
+			problems = append(problems, fmt.Sprintf("entry %d belongs to election %d", entry.Seq, entry.ElectionID))
+		}
+		hashes = append(hashes, entry.Hash)
+		counts[entry.CandidateID]++
+	}
+
+	root, err := ledger.MerkleRoot(hashes)
+	if err != nil {
+		problems = append(problems, err.Error())
+	} else if root != publishedRoot {
+		problems = append(problems, fmt.Sprintf("Merkle root %s does not match the published root %s", root, publishedRoot))
+	}
+	switch {
+	case e.MerkleRoot == nil:
+		problems = append(problems, "no Merkle root is stored with the ledger, the election is not closed yet")
+	case *e.MerkleRoot != publishedRoot:
+		problems = append(problems, fmt.Sprintf("Merkle root %s stored with the ledger is not the published root %s", *e.MerkleRoot, publishedRoot))
+	}
+
+	published := make(map[int]bool)
+	for _, t := range e.Tallies {
+		published[t.CandidateID] = true
+		if counts[t.CandidateID] != t.Votes {
+			problems = append(problems, fmt.Sprintf("candidate %d: published tally %d, ledger has %d ballots", t.CandidateID, t.Votes, counts[t.CandidateID]))
+		}
+	}
+	for candidateID, count := range counts {
+		if !published[candidateID] {
+			problems = append(problems, fmt.Sprintf("candidate %d: %d ballots in the ledger but no published tally", candidateID, count))
+		}
+	}
+	return problems
+}
+
+func readFile(path string) ([]export, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var e export
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	return []export{e}, nil
+}
+
+func readDatabase(driver, path string, electionID int) ([]export, error) {
+	dialect, err := database.NewDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+	dsn := path
+	if dialect.Name() == "sqlite" {
+		dsn = fmt.Sprintf("file:%s?mode=ro", path)
+	}
+	conn, err := sql.Open(dialect.DriverName(), dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	db := database.New(conn, dialect)
+
+	e := export{ElectionID: electionID}
+	var root sql.NullString
+	err = db.QueryRow(`SELECT ledger_root FROM elections WHERE id = ?`, electionID).Scan(&root)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no election %d found in %s", electionID, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if root.Valid {
+		e.MerkleRoot = &root.String
+	}
+
+	if e.Entries, err = readEntries(db, electionID); err != nil {
+		return nil, err
+	}
+	if e.Tallies, err = readTallies(db, electionID); err != nil {
+		return nil, err
+	}
+
+	var unchained int
+	unchainedQuery := `SELECT COUNT(*) FROM ballots b WHERE b.election_id = ? AND NOT EXISTS (SELECT 1 FROM ledger_entries l WHERE l.ballot_id = b.id)`
+	if err := db.QueryRow(unchainedQuery, electionID).Scan(&unchained); err != nil {
+		return nil, err
+	}
+	if unchained > 0 {
+		e.problems = append(e.problems, fmt.Sprintf("%d ballots are not in the ledger", unchained))
+	}
+	return []export{e}, nil
+}
+
+func readEntries(db *database.Database, electionID int) ([]ledger.Entry, error) {
+	// The candidate comes from the ballots table so that a ballot edited
+	// after it was appended shows up as a hash mismatch.
+	query := `
+	SELECT l.election_id, l.seq, l.ballot_id, COALESCE(b.candidate_id, 0), l.cast_date, l.prev_hash, l.hash
+	FROM ledger_entries l LEFT JOIN ballots b ON b.id = l.ballot_id
+	WHERE l.election_id = ? ORDER BY l.seq`
+	rows, err := db.Query(query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]ledger.Entry, 0)
+	for rows.Next() {
+		var e ledger.Entry
+		if err := rows.Scan(&e.ElectionID, &e.Seq, &e.BallotID, &e.CandidateID, &e.CastDate, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func readTallies(db *database.Database, electionID int) ([]tally, error) {
+	rows, err := db.Query(`SELECT id, votes FROM candidates WHERE election_id = ? ORDER BY id`, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tallies := make([]tally, 0)
+	for rows.Next() {
+		var t tally
+		if err := rows.Scan(&t.CandidateID, &t.Votes); err != nil {
+			return nil, err
+		}
+		tallies = append(tallies, t)
+	}
+	return tallies, rows.Err()
+}
@@ -36,15 +36,33 @@ const docTemplate = `{
                 ],
                 "summary": "Get all candidates",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter candidates by election ID",
+                        "name": "election_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter candidates by district ID",
+                        "name": "district_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Filter candidates by name",
                         "name": "name",
                         "in": "query"
                     },
+                    {
+                        "type": "integer",
+                        "description": "Filter candidates by party ID",
+                        "name": "party_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Filter candidates by party",
+                        "description": "Filter candidates by party name or abbreviation",
                         "name": "party",
                         "in": "query"
                     },
@@ -75,16 +93,13 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new candidate with the provided name and party",
+                "description": "Create a new candidate with the provided name and party in an election",
                 "consumes": [
                     "application/json"
                 ],
@@ -115,21 +130,27 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad request - cannot parse JSON or missing required fields",
+                        "description": "Bad request - cannot parse JSON, missing required fields, district or party not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - election is already open",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
@@ -168,28 +189,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request - invalid candidate ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "404": {
                         "description": "Not found - candidate not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
@@ -233,30 +245,27 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid candidate ID or cannot parse JSON",
+                        "description": "Bad request - invalid candidate ID, cannot parse JSON, district or party not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "404": {
                         "description": "Not found - candidate not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - election is already open",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
@@ -295,36 +304,38 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request - invalid candidate ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "404": {
                         "description": "Not found - candidate not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - election is already open",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/election/results": {
+        "/districts": {
             "get": {
-                "description": "Get election results with optional filtering for qualified candidates only",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all electoral districts",
                 "consumes": [
                     "application/json"
                 ],
@@ -332,40 +343,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "election"
-                ],
-                "summary": "Get election results",
-                "parameters": [
-                    {
-                        "type": "boolean",
-                        "description": "Filter only qualified candidates (default: false)",
-                        "name": "qualified",
-                        "in": "query"
-                    }
+                    "district"
                 ],
+                "summary": "Get all districts",
                 "responses": {
                     "200": {
-                        "description": "Election results",
+                        "description": "List of districts",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_district.District"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/election/threshold": {
+            },
             "post": {
-                "description": "Set the minimum vote threshold for candidates to be qualified",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new electoral district (dapil) with its seat count",
                 "consumes": [
                     "application/json"
                 ],
@@ -373,54 +378,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "election"
+                    "district"
                 ],
-                "summary": "Set election threshold",
+                "summary": "Create a new district",
                 "parameters": [
                     {
-                        "description": "Threshold Data",
-                        "name": "threshold",
+                        "description": "District Data",
+                        "name": "district",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_election.SetThresholdInput"
+                            "$ref": "#/definitions/internal_district.CreateDistrictInput"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Threshold set successfully",
+                    "201": {
+                        "description": "District created successfully",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_district.District"
                         }
                     },
                     "400": {
-                        "description": "Bad request - cannot parse JSON or invalid threshold value",
+                        "description": "Bad request - cannot parse JSON or invalid district data",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - district name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/election/time": {
-            "post": {
-                "description": "Set the start and end time for the election",
+        "/districts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific electoral district by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -428,54 +435,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "election"
+                    "district"
                 ],
-                "summary": "Set election time",
+                "summary": "Get district by ID",
                 "parameters": [
                     {
-                        "description": "Election Time Data",
-                        "name": "time",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_election.SetTimeInput"
-                        }
+                        "type": "integer",
+                        "description": "District ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Election time set successfully",
+                        "description": "District details",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_district.District"
                         }
                     },
                     "400": {
-                        "description": "Bad request - cannot parse JSON or invalid time format",
+                        "description": "Bad request - invalid district ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - district not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/login": {
-            "post": {
-                "description": "Login a user and get a JWT token",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an electoral district's name and seat count. A district cannot be changed once an election with candidates in it is closed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -483,63 +488,67 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "district"
                 ],
-                "summary": "Login a user",
+                "summary": "Update district",
                 "parameters": [
                     {
-                        "description": "Login Credentials",
-                        "name": "credentials",
+                        "type": "integer",
+                        "description": "District ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated district data",
+                        "name": "district",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_auth.LoginInput"
+                            "$ref": "#/definitions/internal_district.UpdateDistrictInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful with JWT token",
+                        "description": "Updated district details",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_district.District"
                         }
                     },
                     "400": {
-                        "description": "Bad request - cannot parse JSON",
+                        "description": "Bad request - invalid district ID, cannot parse JSON or invalid district data",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized - invalid credentials",
+                    "404": {
+                        "description": "Not found - district not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - district name already exists, or a closed election uses the district",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/register": {
-            "post": {
-                "description": "Register a new user account with username, password, name, and role",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an electoral district that has no candidates or voters assigned",
                 "consumes": [
                     "application/json"
                 ],
@@ -547,52 +556,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "district"
                 ],
-                "summary": "Register a new user",
+                "summary": "Delete district",
                 "parameters": [
                     {
-                        "description": "User Registration Data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_auth.RegisterInput"
-                        }
+                        "type": "integer",
+                        "description": "District ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User successfully registered",
+                    "200": {
+                        "description": "District deleted successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad request - validation errors or username already exists",
+                        "description": "Bad request - invalid district ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - district not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - district is still in use, or a closed election uses it",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/vote": {
-            "post": {
-                "description": "Cast a vote for a candidate in the election",
+        "/elections": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every election held in this deployment",
                 "consumes": [
                     "application/json"
                 ],
@@ -602,79 +622,83 @@ const docTemplate = `{
                 "tags": [
                     "election"
                 ],
-                "summary": "Cast a vote",
-                "parameters": [
-                    {
-                        "description": "Vote Data",
-                        "name": "vote",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_election.CastVoteInput"
-                        }
-                    }
-                ],
+                "summary": "Get all elections",
                 "responses": {
                     "200": {
-                        "description": "Vote cast successfully",
+                        "description": "List of elections",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_election.Election"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad request - cannot parse JSON or missing required fields",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
-                    },
-                    "403": {
-                        "description": "Forbidden - election is not currently active",
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new election with its own schedule, thresholds and ballot configuration. party_threshold is the percentage of the election vote a party needs to take part in seat allocation; threshold is the minimum number of votes a candidate needs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Create an election",
+                "parameters": [
+                    {
+                        "description": "Election Data",
+                        "name": "election",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_election.CreateElectionInput"
                         }
-                    },
-                    "404": {
-                        "description": "Not found - voter or candidate not found",
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Election created successfully",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_election.Election"
                         }
                     },
-                    "409": {
-                        "description": "Conflict - voter has already voted",
+                    "400": {
+                        "description": "Bad request - cannot parse JSON or invalid election data",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/voters": {
+        "/elections/{id}": {
             "get": {
-                "description": "Get all voters with optional name filtering",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific election by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -682,41 +706,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "voter"
+                    "election"
                 ],
-                "summary": "Get all voters",
+                "summary": "Get election by ID",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter voters by name",
-                        "name": "name",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of voters",
+                        "description": "Election details",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "additionalProperties": true
-                            }
+                            "$ref": "#/definitions/internal_election.Election"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Create a new voter with the provided name",
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an election's schedule, thresholds and ballot configuration",
                 "consumes": [
                     "application/json"
                 ],
@@ -724,61 +759,67 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "voter"
+                    "election"
                 ],
-                "summary": "Create a new voter",
+                "summary": "Update election",
                 "parameters": [
                     {
-                        "description": "Voter Data",
-                        "name": "voter",
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated election data",
+                        "name": "election",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_voter.CreateVoterInput"
+                            "$ref": "#/definitions/internal_election.UpdateElectionInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Voter created successfully",
+                    "200": {
+                        "description": "Updated election details",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/internal_election.Election"
                         }
                     },
                     "400": {
-                        "description": "Bad request - cannot parse JSON or name is required",
+                        "description": "Bad request - invalid election ID, cannot parse JSON or invalid election data",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "409": {
-                        "description": "Conflict - voter name already exists",
+                        "description": "Conflict - change not allowed in the election's current status",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/voters/{id}": {
-            "get": {
-                "description": "Get a specific voter by their ID",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a draft election that has no candidates",
                 "consumes": [
                     "application/json"
                 ],
@@ -786,13 +827,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "voter"
+                    "election"
                 ],
-                "summary": "Get voter by ID",
+                "summary": "Delete election",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Voter ID",
+                        "description": "Election ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -800,43 +841,49 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Voter details",
+                        "description": "Election deleted successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid voter ID",
+                        "description": "Bad request - invalid election ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "404": {
-                        "description": "Not found - voter not found",
+                        "description": "Not found - election not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - election is not a draft or still has candidates",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing voter's information",
+            }
+        },
+        "/elections/{id}/ballot": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the ballot of an election for a district, with candidates listed in the configured ballot order. Pemilih get the ballot of their own district; users with the ballots:read_any permission may pick one with district_id or omit it to see every candidate.",
                 "consumes": [
                     "application/json"
                 ],
@@ -844,75 +891,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "voter"
+                    "election"
                 ],
-                "summary": "Update voter",
+                "summary": "Get election ballot",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Voter ID",
+                        "description": "Election ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated voter data",
-                        "name": "voter",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_voter.UpdateVoterInput"
-                        }
+                        "type": "integer",
+                        "description": "District ID (requires ballots:read_any)",
+                        "name": "district_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated voter details",
+                        "description": "Election ballot",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/internal_election.Ballot"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid voter ID or cannot parse JSON",
+                        "description": "Bad request - invalid election ID or voter is not assigned to a district",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "404": {
-                        "description": "Not found - voter not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict - voter name already exists",
+                        "description": "Not found - election, district or voter not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a voter by their ID",
+            }
+        },
+        "/elections/{id}/ledger": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the size and head hash of an election's hash-chained ballot ledger, its Merkle root and the Merkle root of its receipt hashes. The ledger is built when the election closes, from its ballots in random order, and is empty with null roots before that.",
                 "consumes": [
                     "application/json"
                 ],
@@ -920,13 +952,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "voter"
+                    "election"
                 ],
-                "summary": "Delete voter",
+                "summary": "Get ledger summary",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Voter ID",
+                        "description": "Election ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -934,138 +966,4104 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Voter deleted successfully",
+                        "description": "Ledger summary",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_election.LedgerSummary"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid voter ID",
+                        "description": "Bad request - invalid election ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "404": {
-                        "description": "Not found - voter not found",
+                        "description": "Not found - election not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "internal_auth.LoginInput": {
+        },
+        "/elections/{id}/ledger/entries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export every ledger entry of a closed election together with its Merkle root and candidate tallies, in the format read by the verify-ledger command. Requires the ledger:read permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Export ledger",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ledger export",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.LedgerExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires ledger:read",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - ledger not built yet",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/ledger/proof": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the Merkle inclusion proof of a ledger entry against the root published when the election closed. Requires the ledger:read permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Get ledger inclusion proof",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ledger entry sequence number",
+                        "name": "seq",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inclusion proof",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.LedgerProof"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires ledger:read",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election or ledger entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - ledger root not published yet",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, or ledger_incomplete if entries covered by the root are missing",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/recounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the audit history of recounts run on an election, with the discrepancies each one found",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Get election recounts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recount history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_election.Recount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recompute every candidate's tally from the stored ballots and report each counter that does not match. With repair set, the counters are overwritten with the ballot counts; this is only allowed while the election is closed, before it is certified. Every run is recorded for audit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Recount election results",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether to repair the counters, and an optional note",
+                        "name": "recount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.RecountInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Recount report",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.Recount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID or cannot parse JSON",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - repair requested while the election is not closed, or after it is certified",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the results of an election. With qualified=true only candidates whose party passed the party threshold and who reached the candidate threshold are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Get election results",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include candidates of this district",
+                        "name": "district_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter only qualified candidates (default: false)",
+                        "name": "qualified",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Election results",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/results/districts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the results of an election grouped by district. Candidates without a district are listed last with a null district.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Get election results per district",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Results per district",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_election.DistrictResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/results/parties": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every party's votes and share (in percent) of the election vote, and whether it passed the election's party_threshold",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Get party results",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Party results",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.PartyResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/results/seats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allocate the seats of every district with the Sainte-Laguë method (divisors 1, 3, 5, ...) on party votes, then give each party's seats to its candidates with the most votes. Quotient ties go to the party with more votes, then to the lower party ballot number; candidate ties go to the lower candidate ID. Parties below the election's party_threshold get no seats, and candidates below its threshold cannot take one. A party gets no more seats than it has eligible candidates in the district, and candidates without a district are left out. Once the election is certified, the allocation made at certification is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Get seat allocation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Seat allocation per district",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.SeatAllocation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/results/verification": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check that the tallies reconcile with the stored ballots: one ballot per participating voter, and every candidate's vote count equal to the ballots cast for them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Verify election results",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verification report",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.ResultsVerification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/state": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current lifecycle status of an election and the statuses it can move to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Get election state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Election state",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.ElectionState"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/elections/{id}/transitions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the lifecycle transition history of an election",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Get election transitions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transition history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_election.Transition"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move an election through its lifecycle (draft, scheduled, open, closed, certified)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Transition election status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Election ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target status",
+                        "name": "transition",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.TransitionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New election state",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.ElectionState"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid election ID, cannot parse JSON or unknown status",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires elections:manage, and elections:certify to certify",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - transition not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockouts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the usernames and client addresses that have to wait before they can try to log in again, after failed logins. locked_out is true for those that reached the lockout rather than a short wait.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockout"
+                ],
+                "summary": "Get locked usernames and addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only account (usernames) or address (client addresses)",
+                        "name": "scope",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Locked usernames and addresses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_lockout.Failures"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - unknown scope",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockouts/{scope}/{subject}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Forget the failed logins of a username (scope account) or a client address (scope address), so that it can log in again at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockout"
+                ],
+                "summary": "Unlock a username or an address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "account or address",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username or address",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unlocked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - unknown scope",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - no failed logins counted",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Login a user and start a session. The access token (token) is valid for expires_in seconds; the refresh token gets new tokens from POST /token/refresh, once. A user with two-factor authentication gets two_factor_required and a challenge instead of the tokens, to send with a code to POST /login/2fa. After a few failed logins of a username or from an address, the next has to wait, a little longer each time, and after many it is locked out for a while; the Retry-After header says for how many seconds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login a user",
+                "parameters": [
+                    {
+                        "description": "Login Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.LoginInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful with the access and refresh tokens",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - unknown username or wrong password",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - account is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests - too many failed logins, wait for Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/login-attempts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the latest login attempts, successful or not, optionally only those of a username or from an address. result is succeeded, invalid_credentials, account_disabled, two_factor_pending (right password, code to follow), invalid_code (wrong two-factor or recovery code) or locked_out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockout"
+                ],
+                "summary": "Get login attempts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only attempts with this username",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only attempts from this client address",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of attempts, 100 by default and 1000 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login attempts, the latest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_lockout.Attempt"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - limit is not a positive number",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/2fa": {
+            "post": {
+                "description": "Finish the login of a user with two-factor authentication: send the challenge that POST /login answered the password with, and the code of the authenticator app or a recovery code. A challenge is valid for 5 minutes and 5 wrong codes. Wrong codes count as failed logins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login with a two-factor code",
+                "parameters": [
+                    {
+                        "description": "Challenge and Code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.TwoFactorLoginInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful with the access and refresh tokens",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON or missing challenge or code",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - challenge invalid or expired, or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - account is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests - too many failed logins, wait for Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "End the session of the access token. Its access tokens and refresh token stop working at once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "200": {
+                        "description": "Logged out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get whether two-factor authentication is on, whether the role of the user requires it, and how many recovery codes are left",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get the two-factor authentication of the current user",
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication status",
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.TwoFactorStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a new TOTP secret to add to an authenticator app, as text and as an otpauth URI for a QR code. Two-factor authentication is on once the secret is confirmed with POST /me/2fa/confirm; until then, setting up again gives a new secret.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Set up two-factor authentication",
+                "responses": {
+                    "200": {
+                        "description": "Secret to confirm",
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.TwoFactorSetup"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - two-factor authentication is on already",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn two-factor authentication on with a code of the authenticator app the secret was added to. The answer holds recovery codes that each log in once without the app; they are not shown again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Confirm two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.CodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication is on",
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON, missing or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - two-factor authentication is on already, or was not set up",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn two-factor authentication off with a code of the authenticator app or a recovery code. Users whose role requires two-factor authentication cannot turn it off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Turn off two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.CodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication is off",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON, missing or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - two-factor authentication is off already, or required for the role",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests - too many wrong codes, wait for Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the recovery codes of the current user with new ones, with a code of the authenticator app or a recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get new recovery codes",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.CodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New recovery codes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON, missing or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - two-factor authentication is off",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests - too many wrong codes, wait for Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/language": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Choose the language, en or id, the API answers the logged-in user in, whatever their Accept-Language header says. An empty language follows the header again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Set the language of the current user",
+                "parameters": [
+                    {
+                        "description": "Language",
+                        "name": "language",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.LanguageInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Language updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON or unsupported language",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/parties": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all political parties ordered by ballot number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "party"
+                ],
+                "summary": "Get all parties",
+                "responses": {
+                    "200": {
+                        "description": "List of parties",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_party.Party"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new political party with its ballot number, logo and color",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "party"
+                ],
+                "summary": "Create a new party",
+                "parameters": [
+                    {
+                        "description": "Party Data",
+                        "name": "party",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_party.CreatePartyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Party created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_party.Party"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON or invalid party data",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - party name, abbreviation or ballot number already exists",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/parties/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific political party by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "party"
+                ],
+                "summary": "Get party by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Party ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Party details",
+                        "schema": {
+                            "$ref": "#/definitions/internal_party.Party"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid party ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - party not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a political party's details. A party cannot be changed once an election with candidates of the party is closed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "party"
+                ],
+                "summary": "Update party",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Party ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated party data",
+                        "name": "party",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_party.UpdatePartyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated party details",
+                        "schema": {
+                            "$ref": "#/definitions/internal_party.Party"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid party ID, cannot parse JSON or invalid party data",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - party not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - party name, abbreviation or ballot number already exists, or a closed election has candidates of the party",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a political party that has no candidates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "party"
+                ],
+                "summary": "Delete party",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Party ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Party deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid party ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - party not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - party still has candidates, or a closed election has candidates of the party",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every permission a role can grant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rbac"
+                ],
+                "summary": "Get all permissions",
+                "responses": {
+                    "200": {
+                        "description": "List of permissions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_rbac.Permission"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/receipts/{code}": {
+            "get": {
+                "description": "Check that the ballot a receipt was issued for is recorded and, once the election is closed, get the proof that the receipt hash is in the Merkle tree of receipt hashes published then (receipts_root of the ledger summary). Receipts are not linked to ballots, so neither the candidate nor the place of the ballot in the ledger can be found from a receipt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Look up a vote receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Receipt code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Receipt status",
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.ReceiptStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - receipt not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Register a new voter account with name, username and password. Accounts always get the pemilih role; petugas accounts are created with POST /users or the users create command.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User Registration Data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.RegisterInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User successfully registered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - username already exists",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every role with the permissions it grants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rbac"
+                ],
+                "summary": "Get all roles",
+                "responses": {
+                    "200": {
+                        "description": "List of roles",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_rbac.Role"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{name}/permissions": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the permissions of a role. Only permissions the caller holds can be granted or revoked, the permissions of super_admin are fixed, and users:manage cannot be taken from the last role whose active users hold it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rbac"
+                ],
+                "summary": "Set the permissions of a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New Permissions",
+                        "name": "permissions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_rbac.PermissionsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permissions set successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_rbac.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON or unknown permission",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires roles:manage, or a permission the caller does not hold",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - role not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - role is super_admin, or no active user could manage users afterwards",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/refresh": {
+            "post": {
+                "description": "Swap a refresh token for a new access token and a new refresh token of the same session. Each refresh token works once: using one again ends the session, in case it was stolen.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh the tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "refresh",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.RefreshInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New tokens",
+                        "schema": {
+                            "$ref": "#/definitions/internal_auth.Tokens"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON or missing refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - refresh token invalid, expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - account is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every user account, optionally only those with one role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get all users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only users with this role",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_user.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new staff account, with the petugas role unless another is given. The caller must hold every permission of the role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a staff account",
+                "parameters": [
+                    {
+                        "description": "Account Data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_user.CreateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Account created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON, invalid account data or unknown role",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage and every permission of the role",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - username already exists",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific user account by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User details",
+                        "schema": {
+                            "$ref": "#/definitions/internal_user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - user not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop a user from logging in. Tokens they already hold stop working at once. The caller must hold every permission of the role of the user, and the last active user who can manage users cannot be disabled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Disable a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User disabled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage and every permission of the role of the user",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - user not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - user is the last active user who can manage users",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Let a disabled user log in again. The caller must hold every permission of the role of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Enable a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User enabled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage and every permission of the role of the user",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - user not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Give a user another role. The caller must hold every permission of both the old and the new role, and the last active user who can manage users cannot lose that permission. The pemilih role cannot be given, as voter accounts are made by registering.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change the role of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New Role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_user.ChangeRoleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role changed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid user ID, cannot parse JSON, unknown role or pemilih role",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - requires users:manage and every permission of both roles",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - user not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - user is the last active user who can manage users",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/voters": {
+            "get": {
+                "description": "Get all voters with optional name filtering",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voter"
+                ],
+                "summary": "Get all voters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter voters by name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter voters by district ID",
+                        "name": "district_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of voters",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new voter with the provided name and optional district",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voter"
+                ],
+                "summary": "Create a new voter",
+                "parameters": [
+                    {
+                        "description": "Voter Data",
+                        "name": "voter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_voter.CreateVoterInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Voter created successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON, name is required or district not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - voter name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/voters/{id}": {
+            "get": {
+                "description": "Get a specific voter by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voter"
+                ],
+                "summary": "Get voter by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Voter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Voter details",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid voter ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - voter not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing voter's information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voter"
+                ],
+                "summary": "Update voter",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Voter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated voter data",
+                        "name": "voter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_voter.UpdateVoterInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated voter details",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid voter ID, cannot parse JSON or district not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - voter not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - voter name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a voter by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voter"
+                ],
+                "summary": "Delete voter",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Voter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Voter deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid voter ID",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - voter not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/votes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cast a vote for a candidate as the voter linked to the logged-in account. Users with the votes:assist permission may set voter_id to record an assisted vote for another voter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "election"
+                ],
+                "summary": "Cast a vote",
+                "parameters": [
+                    {
+                        "description": "Vote Data",
+                        "name": "vote",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_election.CastVoteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Vote cast successfully, with the receipt code to look the ballot up later",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - cannot parse JSON or missing required fields",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - election is not currently active, candidate is outside the voter's district or voter_id used without the votes:assist permission",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found - voter, candidate or election not found",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - voter has already voted in this election",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/legiskuy-backend_pkg_apperror.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "internal_auth.CodeInput": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code is the code of the authenticator app, or a recovery code where a\nlost app is no reason to refuse.",
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "internal_auth.LanguageInput": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "description": "Language is en or id, or \"\" to follow the Accept-Language header again.",
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "id"
+                    ],
+                    "example": "id"
+                }
+            }
+        },
+        "internal_auth.LoginInput": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_auth.RecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "description": "Codes each log in once in place of a code of the authenticator app.\nThey are shown only now.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "abcd-efgh-ijkl-mnop"
+                    ]
+                }
+            }
+        },
+        "internal_auth.RefreshInput": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_auth.RegisterInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "language": {
+                    "description": "Language is the language the API answers the user in once logged in,\nen or id. Leave it out to follow the Accept-Language header.",
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "id"
+                    ],
+                    "example": "id"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "description": "Password must be at least 8 characters long and contain both letters\nand digits.",
+                    "type": "string",
+                    "minLength": 8
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                }
+            }
+        },
+        "internal_auth.Tokens": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "ExpiresIn is the number of seconds the access token is valid for.",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "description": "RefreshToken gets the next Tokens from POST /token/refresh, once.",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "AccessToken is the JWT sent as bearer token.",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_auth.TwoFactorLoginInput": {
+            "type": "object",
+            "required": [
+                "challenge",
+                "code"
+            ],
+            "properties": {
+                "challenge": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Code is the code of the authenticator app or a recovery code.",
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "internal_auth.TwoFactorSetup": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "description": "URI is the secret as an otpauth URI, to show as a QR code.",
+                    "type": "string",
+                    "example": "otpauth://totp/LegisKuy:budi?issuer=LegisKuy\u0026secret=JBSWY3DPEHPK3PXP"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "internal_auth.TwoFactorStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "recovery_codes_left": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "required": {
+                    "description": "Required is whether the user has to turn it on before they can do\nanything else.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_candidate.CreateCandidateInput": {
+            "type": "object",
+            "required": [
+                "election_id",
+                "name",
+                "party_id"
+            ],
+            "properties": {
+                "district_id": {
+                    "type": "integer"
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "party_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_candidate.UpdateCandidateInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "party_id"
+            ],
+            "properties": {
+                "district_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "party_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_district.CreateDistrictInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "seats": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_district.District": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "seats": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_district.UpdateDistrictInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "seats": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.Ballot": {
+            "type": "object",
+            "properties": {
+                "district_id": {
+                    "type": "integer"
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.BallotEntry"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_election.BallotEntry": {
+            "type": "object",
+            "properties": {
+                "candidate_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "party": {
+                    "$ref": "#/definitions/legiskuy-backend_internal_party.Party"
+                }
+            }
+        },
+        "internal_election.CandidateMismatch": {
+            "type": "object",
+            "properties": {
+                "ballots": {
+                    "type": "integer"
+                },
+                "candidate_id": {
+                    "type": "integer"
+                },
+                "tallied": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.CandidateTally": {
+            "type": "object",
+            "properties": {
+                "candidate_id": {
+                    "type": "integer"
+                },
+                "votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.CastVoteInput": {
+            "type": "object",
+            "required": [
+                "candidate_id"
+            ],
+            "properties": {
+                "candidate_id": {
+                    "type": "integer"
+                },
+                "voter_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.CreateElectionInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "ballot_order": {
+                    "type": "string",
+                    "enum": [
+                        "id",
+                        "name",
+                        "party"
+                    ]
+                },
+                "ballot_title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "party_threshold": {
+                    "type": "number"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.DistrictResult": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/legiskuy-backend_internal_candidate.Candidate"
+                    }
+                },
+                "district": {
+                    "$ref": "#/definitions/legiskuy-backend_internal_district.District"
+                },
+                "total_votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.DistrictSeats": {
+            "type": "object",
+            "properties": {
+                "district": {
+                    "$ref": "#/definitions/legiskuy-backend_internal_district.District"
+                },
+                "parties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.PartySeats"
+                    }
+                },
+                "rounds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.SeatRound"
+                    }
+                },
+                "seats_filled": {
+                    "type": "integer"
+                },
+                "total_votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.Election": {
+            "type": "object",
+            "properties": {
+                "ballot_order": {
+                    "type": "string"
+                },
+                "ballot_title": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "party_threshold": {
+                    "type": "number"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.ElectionState": {
+            "type": "object",
+            "properties": {
+                "allowed_transitions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_election.LedgerExport": {
+            "type": "object",
+            "properties": {
+                "election_id": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/legiskuy-backend_pkg_ledger.Entry"
+                    }
+                },
+                "merkle_root": {
+                    "type": "string"
+                },
+                "tallies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.CandidateTally"
+                    }
+                }
+            }
+        },
+        "internal_election.LedgerProof": {
+            "type": "object",
+            "properties": {
+                "election_id": {
+                    "type": "integer"
+                },
+                "entry": {
+                    "$ref": "#/definitions/legiskuy-backend_pkg_ledger.Entry"
+                },
+                "merkle_root": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/legiskuy-backend_pkg_ledger.ProofStep"
+                    }
+                }
+            }
+        },
+        "internal_election.LedgerSummary": {
+            "type": "object",
+            "properties": {
+                "election_id": {
+                    "type": "integer"
+                },
+                "head_hash": {
+                    "type": "string"
+                },
+                "merkle_root": {
+                    "type": "string"
+                },
+                "receipts_root": {
+                    "description": "ReceiptsRoot is the Merkle root of the receipt hashes, which receipt\nlookups prove inclusion against.",
+                    "type": "string"
+                },
+                "root_size": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.PartyResult": {
+            "type": "object",
+            "properties": {
+                "party": {
+                    "$ref": "#/definitions/legiskuy-backend_internal_party.Party"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "share": {
+                    "type": "number"
+                },
+                "votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.PartyResults": {
+            "type": "object",
+            "properties": {
+                "election_id": {
+                    "type": "integer"
+                },
+                "parties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.PartyResult"
+                    }
+                },
+                "party_threshold": {
+                    "type": "number"
+                },
+                "total_votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.PartySeats": {
+            "type": "object",
+            "properties": {
+                "elected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/legiskuy-backend_internal_candidate.Candidate"
+                    }
+                },
+                "party": {
+                    "$ref": "#/definitions/legiskuy-backend_internal_party.Party"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "seats": {
+                    "type": "integer"
+                },
+                "votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.ReceiptStatus": {
+            "type": "object",
+            "properties": {
+                "election_id": {
+                    "type": "integer"
+                },
+                "election_status": {
+                    "type": "string"
+                },
+                "included": {
+                    "type": "boolean"
+                },
+                "receipt_hash": {
+                    "type": "string"
+                },
+                "receipts_root": {
+                    "type": "string"
+                },
+                "recorded": {
+                    "type": "boolean"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/legiskuy-backend_pkg_ledger.ProofStep"
+                    }
+                }
+            }
+        },
+        "internal_election.Recount": {
+            "type": "object",
+            "properties": {
+                "ballots": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.CandidateMismatch"
+                    }
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "participations": {
+                    "type": "integer"
+                },
+                "repair": {
+                    "type": "boolean"
+                },
+                "repaired": {
+                    "type": "integer"
+                },
+                "tallied_votes": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.RecountInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "repair": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_election.ResultsVerification": {
+            "type": "object",
+            "properties": {
+                "ballots": {
+                    "type": "integer"
+                },
+                "consistent": {
+                    "type": "boolean"
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "mismatches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.CandidateMismatch"
+                    }
+                },
+                "participations": {
+                    "type": "integer"
+                },
+                "tallied_votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.SeatAllocation": {
             "type": "object",
             "properties": {
-                "password": {
+                "districts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.DistrictSeats"
+                    }
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "parties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_election.PartyResult"
+                    }
+                },
+                "party_threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_election.SeatRound": {
+            "type": "object",
+            "properties": {
+                "party_id": {
+                    "type": "integer"
+                },
+                "quotient": {
+                    "type": "number"
+                },
+                "seat": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.Transition": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "from_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_election.TransitionInput": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_election.UpdateElectionInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "ballot_order": {
+                    "type": "string",
+                    "enum": [
+                        "id",
+                        "name",
+                        "party"
+                    ]
+                },
+                "ballot_title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "party_threshold": {
+                    "type": "number"
+                },
+                "start_time": {
                     "type": "string"
                 },
+                "threshold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_lockout.Attempt": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "attempted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "string",
+                    "example": "invalid_credentials"
+                },
+                "user_id": {
+                    "description": "UserID is the account of Username, if there is one.",
+                    "type": "integer"
+                },
                 "username": {
+                    "type": "string",
+                    "example": "budi"
+                }
+            }
+        },
+        "internal_lockout.Failures": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "last_failed_at": {
+                    "type": "string"
+                },
+                "locked_out": {
+                    "description": "LockedOut is whether the failures reached the lockout, rather than\nthe subject only having to wait a little.",
+                    "type": "boolean"
+                },
+                "locked_until": {
+                    "description": "LockedUntil is when the subject may try to log in again.",
                     "type": "string"
+                },
+                "scope": {
+                    "description": "Scope is account or address.",
+                    "type": "string",
+                    "example": "account"
+                },
+                "subject": {
+                    "description": "Subject is the username or the address.",
+                    "type": "string",
+                    "example": "budi"
                 }
             }
         },
-        "internal_auth.RegisterInput": {
+        "internal_party.CreatePartyInput": {
+            "type": "object",
+            "required": [
+                "abbreviation",
+                "name"
+            ],
+            "properties": {
+                "abbreviation": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "ballot_number": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "logo_url": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "internal_party.Party": {
+            "type": "object",
+            "properties": {
+                "abbreviation": {
+                    "type": "string"
+                },
+                "ballot_number": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_party.UpdatePartyInput": {
+            "type": "object",
+            "required": [
+                "abbreviation",
+                "name"
+            ],
+            "properties": {
+                "abbreviation": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "ballot_number": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "logo_url": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "internal_rbac.Permission": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_rbac.PermissionsInput": {
+            "type": "object",
+            "required": [
+                "permissions"
+            ],
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "recounts:read",
+                        "ballots:read_any"
+                    ]
+                }
+            }
+        },
+        "internal_rbac.Role": {
             "type": "object",
             "properties": {
+                "description": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_user.ChangeRoleInput": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "petugas"
+                }
+            }
+        },
+        "internal_user.CreateUserInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
                 "password": {
+                    "description": "Password must be at least 8 characters long and contain both letters\nand digits.",
+                    "type": "string",
+                    "minLength": 8
+                },
+                "role": {
+                    "description": "Role defaults to petugas. It cannot be pemilih: voters register\nthemselves, which links their account to a voter.",
+                    "type": "string",
+                    "example": "kpps"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                }
+            }
+        },
+        "internal_user.User": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
                 },
                 "role": {
                     "type": "string"
                 },
+                "two_factor": {
+                    "description": "TwoFactor is whether the user turned on two-factor authentication.",
+                    "type": "boolean"
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
-        "internal_candidate.CreateCandidateInput": {
+        "internal_voter.CreateVoterInput": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
+                "district_id": {
+                    "type": "integer"
+                },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "internal_voter.UpdateVoterInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "district_id": {
+                    "type": "integer"
                 },
-                "party": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
                 }
             }
         },
-        "internal_candidate.UpdateCandidateInput": {
+        "legiskuy-backend_internal_candidate.Candidate": {
             "type": "object",
             "properties": {
+                "district_id": {
+                    "type": "integer"
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
                 "party": {
-                    "type": "string"
+                    "$ref": "#/definitions/legiskuy-backend_internal_party.Party"
+                },
+                "party_id": {
+                    "type": "integer"
+                },
+                "votes": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_election.CastVoteInput": {
+        "legiskuy-backend_internal_district.District": {
             "type": "object",
             "properties": {
-                "candidate_id": {
+                "id": {
                     "type": "integer"
                 },
-                "voter_id": {
+                "name": {
+                    "type": "string"
+                },
+                "seats": {
                     "type": "integer"
                 }
             }
         },
-        "internal_election.SetThresholdInput": {
+        "legiskuy-backend_internal_party.Party": {
             "type": "object",
             "properties": {
-                "threshold": {
+                "abbreviation": {
+                    "type": "string"
+                },
+                "ballot_number": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "internal_election.SetTimeInput": {
+        "legiskuy-backend_pkg_apperror.FieldError": {
             "type": "object",
             "properties": {
-                "end_time": {
+                "code": {
                     "type": "string"
                 },
-                "start_time": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "internal_voter.CreateVoterInput": {
+        "legiskuy-backend_pkg_apperror.Response": {
             "type": "object",
             "properties": {
-                "name": {
+                "code": {
+                    "type": "string",
+                    "example": "validation_failed"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/legiskuy-backend_pkg_apperror.FieldError"
+                    }
+                },
+                "error": {
+                    "type": "string",
+                    "example": "name is required"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f1c9a52-7c0e-4b8e-9d53-0c1f0f4a6b2e"
+                }
+            }
+        },
+        "legiskuy-backend_pkg_ledger.Entry": {
+            "type": "object",
+            "properties": {
+                "ballot_id": {
+                    "type": "string"
+                },
+                "candidate_id": {
+                    "type": "integer"
+                },
+                "cast_date": {
+                    "type": "string"
+                },
+                "election_id": {
+                    "type": "integer"
+                },
+                "hash": {
                     "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_voter.UpdateVoterInput": {
+        "legiskuy-backend_pkg_ledger.ProofStep": {
             "type": "object",
             "properties": {
-                "name": {
+                "hash": {
+                    "type": "string"
+                },
+                "position": {
                     "type": "string"
                 }
             }
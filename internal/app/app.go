@@ -0,0 +1,227 @@
+// Package app assembles the HTTP application: repositories, services,
+// handlers and routes, all working on the database given in Config.
+package app
+
+import (
+	"legiskuy-backend/internal/auth"
+	"legiskuy-backend/internal/candidate"
+	"legiskuy-backend/internal/district"
+	"legiskuy-backend/internal/election"
+	"legiskuy-backend/internal/lockout"
+	"legiskuy-backend/internal/party"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/internal/user"
+	"legiskuy-backend/internal/voter"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/i18n"
+	"legiskuy-backend/pkg/jwtkeys"
+	"legiskuy-backend/pkg/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+
+	_ "legiskuy-backend/docs"
+
+	"github.com/gofiber/swagger"
+)
+
+type Config struct {
+	// DB must already be migrated, see database.Migrate.
+	DB *database.Database
+	// Keys sign the access tokens and verify them.
+	Keys *jwtkeys.KeySet
+	// AccessTokenTTL and RefreshTokenTTL default to those of package auth
+	// when zero.
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// Lockout limits failed logins, with the defaults of package lockout
+	// for its zero fields.
+	Lockout lockout.Policy
+	// TwoFactor says whose accounts need two-factor authentication.
+	TwoFactor auth.TwoFactorPolicy
+	// ProxyHeader is the header, such as X-Forwarded-For, that holds the
+	// client address when the app runs behind a reverse proxy. Failed
+	// logins are counted per client address, so without it every client
+	// would count as the proxy.
+	ProxyHeader string
+	// TrustedProxies are the addresses and CIDR ranges of the proxies whose
+	// ProxyHeader is believed. Any other client is counted by its own
+	// address, so that it cannot pass for someone else.
+	TrustedProxies []string
+	// BallotDelay is how long a ballot waits to be stored together with
+	// others, election.DefaultBallotDelay when zero.
+	BallotDelay time.Duration
+	// Logger logs every request.
+	Logger bool
+}
+
+// New builds the Fiber app. Nothing is shared between apps built from
+// different configs, so several can run side by side in one process.
+func New(cfg Config) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler,
+		ProxyHeader:  cfg.ProxyHeader,
+		// Without the check, any client could set the header.
+		EnableTrustedProxyCheck: cfg.ProxyHeader != "",
+		TrustedProxies:          cfg.TrustedProxies,
+	})
+
+	app.Use(requestid.New())
+	app.Use(i18n.New())
+	if cfg.Logger {
+		app.Use(logger.New())
+	}
+
+	api := app.Group("/api")
+	v1 := api.Group("/v1")
+
+	voterRepo := voter.NewRepository(cfg.DB)
+	authRepo := auth.NewRepository(cfg.DB)
+	lockoutRepo := lockout.NewRepository(cfg.DB)
+	lockoutService := lockout.NewService(lockoutRepo, cfg.Lockout)
+	lockoutHandler := lockout.NewHandler(lockoutService)
+
+	authService := auth.NewService(authRepo, voterRepo, lockoutService, auth.TokenConfig{
+		Keys:            cfg.Keys,
+		AccessTokenTTL:  cfg.AccessTokenTTL,
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
+	}, cfg.TwoFactor)
+	authHandler := auth.NewHandler(authService)
+	v1.Post("/register", authHandler.Register)
+	v1.Post("/login", authHandler.Login)
+	v1.Post("/login/2fa", authHandler.LoginTwoFactor)
+	v1.Post("/token/refresh", authHandler.Refresh)
+	app.Get("/.well-known/jwks.json", authHandler.JWKS)
+
+	districtRepo := district.NewRepository(cfg.DB)
+	districtService := district.NewService(districtRepo)
+	districtHandler := district.NewHandler(districtService)
+
+	partyRepo := party.NewRepository(cfg.DB)
+	partyService := party.NewService(partyRepo)
+	partyHandler := party.NewHandler(partyService)
+
+	candidateRepo := candidate.NewRepository(cfg.DB)
+	electionRepo := election.NewRepository(cfg.DB)
+
+	electionService := election.NewService(electionRepo, voterRepo, candidateRepo, districtRepo, cfg.BallotDelay)
+	electionHandler := election.NewHandler(electionService)
+	v1.Get("/receipts/:code", electionHandler.LookupReceipt)
+
+	rbacRepo := rbac.NewRepository(cfg.DB)
+	rbacService := rbac.NewService(rbacRepo)
+	rbacHandler := rbac.NewHandler(rbacService)
+
+	userRepo := user.NewRepository(cfg.DB)
+	userService := user.NewService(userRepo, rbacRepo)
+	userHandler := user.NewHandler(userService)
+
+	protected := v1.Group("/", middleware.Protected(cfg.Keys, authService.SessionActive), middleware.LoadAccount(func(userID int) (*middleware.Account, error) {
+		u, err := userRepo.FindByID(userID)
+		if err != nil || u == nil {
+			return nil, err
+		}
+		permissions, err := rbacRepo.FindPermissions(u.Role)
+		if err != nil {
+			return nil, err
+		}
+		return &middleware.Account{
+			Permissions:      permissions,
+			Language:         u.Language,
+			Disabled:         u.Disabled,
+			TwoFactorMissing: cfg.TwoFactor.Requires(u.Role) && !u.TwoFactor,
+		}, nil
+	}))
+	protected.Post("/logout", authHandler.Logout)
+	protected.Put("/me/language", authHandler.SetLanguage)
+	protected.Get("/me/2fa", authHandler.GetTwoFactor)
+	protected.Post("/me/2fa", authHandler.SetUpTwoFactor)
+	protected.Post("/me/2fa/confirm", authHandler.ConfirmTwoFactor)
+	protected.Post("/me/2fa/disable", authHandler.DisableTwoFactor)
+	protected.Post("/me/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
+	// Routes registered from here on are only reached by users who turned
+	// on two-factor authentication if their role requires it. Those above
+	// answer without going on to this middleware.
+	protected.Use(middleware.RequireTwoFactor())
+
+	candidateService := candidate.NewService(candidateRepo, electionService, districtRepo, partyRepo)
+	candidateHandler := candidate.NewHandler(candidateService)
+
+	manageUsers := middleware.RequirePermission(rbac.ManageUsers)
+
+	protected.Get("/users", manageUsers, userHandler.GetAllUsers)
+	protected.Get("/users/:id", manageUsers, userHandler.GetUserByID)
+	protected.Post("/users", manageUsers, userHandler.CreateUser)
+	protected.Put("/users/:id/role", manageUsers, userHandler.ChangeRole)
+	protected.Post("/users/:id/disable", manageUsers, userHandler.DisableUser)
+	protected.Post("/users/:id/enable", manageUsers, userHandler.EnableUser)
+
+	protected.Get("/lockouts", manageUsers, lockoutHandler.GetLocked)
+	protected.Delete("/lockouts/:scope/:subject", manageUsers, lockoutHandler.Unlock)
+	protected.Get("/login-attempts", manageUsers, lockoutHandler.GetAttempts)
+
+	protected.Get("/roles", manageUsers, rbacHandler.GetAllRoles)
+	protected.Get("/permissions", manageUsers, rbacHandler.GetAllPermissions)
+	protected.Put("/roles/:name/permissions", middleware.RequirePermission(rbac.ManageRoles), rbacHandler.SetPermissions)
+
+	manageCandidates := middleware.RequirePermission(rbac.ManageCandidates)
+	protected.Post("/candidates", manageCandidates, candidateHandler.CreateCandidate)
+	protected.Put("/candidates/:id", manageCandidates, candidateHandler.UpdateCandidate)
+	protected.Delete("/candidates/:id", manageCandidates, candidateHandler.DeleteCandidate)
+
+	voterService := voter.NewService(voterRepo, districtRepo)
+	voterHandler := voter.NewHandler(voterService)
+
+	manageVoters := middleware.RequirePermission(rbac.ManageVoters)
+	protected.Post("/voters", manageVoters, voterHandler.CreateVoter)
+	protected.Put("/voters/:id", manageVoters, voterHandler.UpdateVoter)
+	protected.Delete("/voters/:id", manageVoters, voterHandler.DeleteVoter)
+
+	manageDistricts := middleware.RequirePermission(rbac.ManageDistricts)
+	protected.Post("/districts", manageDistricts, districtHandler.CreateDistrict)
+	protected.Put("/districts/:id", manageDistricts, districtHandler.UpdateDistrict)
+	protected.Delete("/districts/:id", manageDistricts, districtHandler.DeleteDistrict)
+
+	manageParties := middleware.RequirePermission(rbac.ManageParties)
+	protected.Post("/parties", manageParties, partyHandler.CreateParty)
+	protected.Put("/parties/:id", manageParties, partyHandler.UpdateParty)
+	protected.Delete("/parties/:id", manageParties, partyHandler.DeleteParty)
+
+	manageElections := middleware.RequirePermission(rbac.ManageElections)
+	protected.Post("/elections", manageElections, electionHandler.CreateElection)
+	protected.Put("/elections/:id", manageElections, electionHandler.UpdateElection)
+	protected.Delete("/elections/:id", manageElections, electionHandler.DeleteElection)
+	protected.Post("/elections/:id/transitions", manageElections, electionHandler.TransitionElection)
+	protected.Post("/elections/:id/recounts", middleware.RequirePermission(rbac.RunRecounts), electionHandler.Recount)
+	protected.Get("/elections/:id/recounts", middleware.RequirePermission(rbac.ReadRecounts), electionHandler.GetRecounts)
+
+	protected.Get("/candidates", candidateHandler.GetAllCandidates)
+	protected.Get("/candidates/:id", candidateHandler.GetCandidateByID)
+	protected.Get("/voters", voterHandler.GetAllVoters)
+	protected.Get("/voters/:id", voterHandler.GetVoterByID)
+	protected.Get("/districts", districtHandler.GetAllDistricts)
+	protected.Get("/districts/:id", districtHandler.GetDistrictByID)
+	protected.Get("/parties", partyHandler.GetAllParties)
+	protected.Get("/parties/:id", partyHandler.GetPartyByID)
+	protected.Get("/elections", electionHandler.GetAllElections)
+	protected.Get("/elections/:id", electionHandler.GetElectionByID)
+	protected.Get("/elections/:id/ballot", electionHandler.GetBallot)
+	protected.Get("/elections/:id/state", electionHandler.GetElectionState)
+	protected.Get("/elections/:id/transitions", electionHandler.GetTransitions)
+	protected.Get("/elections/:id/results", electionHandler.GetResults)
+	protected.Get("/elections/:id/results/districts", electionHandler.GetDistrictResults)
+	protected.Get("/elections/:id/results/parties", electionHandler.GetPartyResults)
+	protected.Get("/elections/:id/results/seats", electionHandler.GetSeatAllocation)
+	protected.Get("/elections/:id/results/verification", electionHandler.VerifyResults)
+	protected.Get("/elections/:id/ledger", electionHandler.GetLedger)
+	readLedger := middleware.RequirePermission(rbac.ReadLedger)
+	protected.Get("/elections/:id/ledger/entries", readLedger, electionHandler.ExportLedger)
+	protected.Get("/elections/:id/ledger/proof", readLedger, electionHandler.GetLedgerProof)
+	protected.Post("/votes", electionHandler.CastVote)
+
+	app.Get("/swagger/*", swagger.HandlerDefault)
+
+	return app
+}
@@ -1,5 +1,7 @@
 package auth
 
+import "time"
+
 type User struct {
 	ID       int
 	Name     string
@@ -7,18 +9,26 @@ type User struct {
 	Password string
 	Role     string
 	HasVoted bool
+	Disabled bool
+	// Language is the language the API answers the user in, or "" to follow
+	// the Accept-Language header.
+	Language string
 }
 
 type RegisterInput struct {
-	Name     string `json:"name"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Role     string `json:"role"`
+	Name     string `json:"name" validate:"trim,required,max=100"`
+	Username string `json:"username" validate:"trim,required,min=3,max=32,username"`
+	// Password must be at least 8 characters long and contain both letters
+	// and digits.
+	Password string `json:"password" validate:"required,min=8,password"`
+	// Language is the language the API answers the user in once logged in,
+	// en or id. Leave it out to follow the Accept-Language header.
+	Language string `json:"language" example:"id" validate:"oneof=en id"`
 }
 
 type LoginInput struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" validate:"trim,required"`
+	Password string `json:"password" validate:"required"`
 }
 
 type UserResponse struct {
@@ -26,4 +36,112 @@ type UserResponse struct {
 	Name     string `json:"name"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	Language string `json:"language,omitempty"`
+}
+
+type LanguageInput struct {
+	// Language is en or id, or "" to follow the Accept-Language header again.
+	Language string `json:"language" example:"id" validate:"oneof=en id"`
+}
+
+// Session is one login of a user, from which every refresh token they get
+// descends.
+type Session struct {
+	ID        string
+	UserID    int
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// RefreshToken is a stored refresh token. Only the hash of the token is
+// kept.
+type RefreshToken struct {
+	Hash      string
+	SessionID string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// Tokens are what a login or a refresh hands out.
+type Tokens struct {
+	// AccessToken is the JWT sent as bearer token.
+	AccessToken string `json:"token"`
+	// RefreshToken gets the next Tokens from POST /token/refresh, once.
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the number of seconds the access token is valid for.
+	ExpiresIn int `json:"expires_in" example:"900"`
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"trim,required"`
+}
+
+// LoginResult is what a login with the right password gets: the tokens, or a
+// challenge to answer with a code if the user turned on two-factor
+// authentication.
+type LoginResult struct {
+	Tokens    *Tokens
+	Challenge *Challenge
+}
+
+// Challenge is the first step of a login with two-factor authentication.
+type Challenge struct {
+	// Challenge is sent with the code to POST /login/2fa.
+	Challenge string `json:"challenge"`
+	// ExpiresIn is the number of seconds the challenge can be answered for.
+	ExpiresIn int `json:"expires_in" example:"300"`
+}
+
+// LoginChallenge is a stored challenge. Only the hash of the challenge is
+// kept.
+type LoginChallenge struct {
+	Hash      string
+	UserID    int
+	ExpiresAt time.Time
+	// Failures is the number of wrong codes sent with the challenge.
+	Failures int
+	UsedAt   *time.Time
+}
+
+type TwoFactorLoginInput struct {
+	Challenge string `json:"challenge" validate:"trim,required"`
+	// Code is the code of the authenticator app or a recovery code.
+	Code string `json:"code" example:"123456" validate:"trim,required"`
+}
+
+// TOTPSecret is the secret a user shares with their authenticator app.
+type TOTPSecret struct {
+	UserID int
+	Secret string
+	// ConfirmedAt is when the user confirmed the secret with a code, nil
+	// while they are setting it up.
+	ConfirmedAt *time.Time
+	LastStep    int64
+}
+
+type CodeInput struct {
+	// Code is the code of the authenticator app, or a recovery code where a
+	// lost app is no reason to refuse.
+	Code string `json:"code" example:"123456" validate:"trim,required"`
+}
+
+type TwoFactorStatus struct {
+	Enabled bool `json:"enabled"`
+	// Required is whether the user has to turn it on before they can do
+	// anything else.
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left" example:"10"`
+}
+
+// TwoFactorSetup is the secret to add to an authenticator app.
+type TwoFactorSetup struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	// URI is the secret as an otpauth URI, to show as a QR code.
+	URI string `json:"otpauth_uri" example:"otpauth://totp/LegisKuy:budi?issuer=LegisKuy&secret=JBSWY3DPEHPK3PXP"`
+}
+
+type RecoveryCodes struct {
+	// Codes each log in once in place of a code of the authenticator app.
+	// They are shown only now.
+	Codes []string `json:"recovery_codes" example:"abcd-efgh-ijkl-mnop"`
 }
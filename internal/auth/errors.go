@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"net/http"
+)
+
+var (
+	ErrUsernameTaken      = apperror.Conflict("username_taken", "username already exists")
+	ErrUserNotFound       = apperror.New(http.StatusUnauthorized, "user_not_found", "user not found")
+	ErrInvalidCredentials = apperror.New(http.StatusUnauthorized, "invalid_credentials", "invalid credentials")
+	ErrInvalidRefresh     = apperror.New(http.StatusUnauthorized, "invalid_refresh_token", "refresh token is invalid or expired")
+	// ErrRefreshReused is returned when a refresh token is used a second
+	// time. One of the two users is not its owner, so the whole session is
+	// ended.
+	ErrRefreshReused    = apperror.New(http.StatusUnauthorized, "refresh_token_reused", "refresh token was already used, the session has been ended")
+	ErrInvalidChallenge = apperror.New(http.StatusUnauthorized, "invalid_challenge", "login challenge is invalid or expired, log in again")
+	ErrInvalidCode      = apperror.New(http.StatusUnauthorized, "invalid_code", "code is wrong")
+	// ErrWrongCode is ErrInvalidCode for a user who is logged in already,
+	// who should not take a 401 to mean that their token is bad.
+	ErrWrongCode         = apperror.Field("code", "invalid", "code is wrong")
+	ErrTwoFactorEnabled  = apperror.Conflict("two_factor_enabled", "two-factor authentication is on already")
+	ErrTwoFactorDisabled = apperror.Conflict("two_factor_disabled", "two-factor authentication is off")
+	ErrNoTwoFactorSetup  = apperror.Conflict("no_two_factor_setup", "start setting up two-factor authentication first")
+	ErrTwoFactorRequired = apperror.Conflict("two_factor_required", "two-factor authentication is required for your role")
+)
@@ -1,6 +1,12 @@
 package auth
 
 import (
+	"errors"
+	"legiskuy-backend/internal/lockout"
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/i18n"
+	"legiskuy-backend/pkg/middleware"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,66 +21,344 @@ func NewHandler(service Service) *Handler {
 }
 
 // @Summary Register a new user
-// @Description Register a new user account with username, password, name, and role
+// @Description Register a new voter account with name, username and password. Accounts always get the pemilih role; petugas accounts are created with POST /users or the users create command.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param user body RegisterInput true "User Registration Data"
 // @Success 201 {object} map[string]interface{} "User successfully registered"
-// @Failure 400 {object} map[string]string "Bad request - validation errors or username already exists"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - validation errors"
+// @Failure 409 {object} apperror.Response "Conflict - username already exists"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /register [post]
 func (h *Handler) Register(c *fiber.Ctx) error {
 	input := new(RegisterInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
+		return apperror.ErrInvalidJSON
 	}
 
 	user, err := h.service.Register(input)
 	if err != nil {
-		if err.Error() == "username already exists" || err.Error() == "name is required" || err.Error() == "username is required" || err.Error() == "password is required" || err.Error() == "role is required" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to register user",
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(user)
 }
 
 // @Summary Login a user
-// @Description Login a user and get a JWT token
+// @Description Login a user and start a session. The access token (token) is valid for expires_in seconds; the refresh token gets new tokens from POST /token/refresh, once. A user with two-factor authentication gets two_factor_required and a challenge instead of the tokens, to send with a code to POST /login/2fa. After a few failed logins of a username or from an address, the next has to wait, a little longer each time, and after many it is locked out for a while; the Retry-After header says for how many seconds.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body LoginInput true "Login Credentials"
-// @Success 200 {object} map[string]string "Login successful with JWT token"
-// @Failure 400 {object} map[string]string "Bad request - cannot parse JSON"
-// @Failure 401 {object} map[string]string "Unauthorized - invalid credentials"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Success 200 {object} map[string]interface{} "Login successful with the access and refresh tokens"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON"
+// @Failure 401 {object} apperror.Response "Unauthorized - unknown username or wrong password"
+// @Failure 403 {object} apperror.Response "Forbidden - account is disabled"
+// @Failure 429 {object} apperror.Response "Too many requests - too many failed logins, wait for Retry-After seconds"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /login [post]
 func (h *Handler) Login(c *fiber.Ctx) error {
 	input := new(LoginInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
+		return apperror.ErrInvalidJSON
 	}
 
-	token, err := h.service.Login(input)
+	result, err := h.service.Login(input, c.IP())
 	if err != nil {
-		if err.Error() == "user not found" || err.Error() == "invalid credentials" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to login",
+		return retryAfter(c, err)
+	}
+	if result.Challenge != nil {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"two_factor_required": true,
+			"challenge":           result.Challenge.Challenge,
+			"expires_in":          result.Challenge.ExpiresIn,
+			"message":             i18n.Message(c, "two_factor_code_needed", "Enter the code of your authenticator app"),
 		})
 	}
+	return loggedIn(c, result.Tokens)
+}
+
+// @Summary Login with a two-factor code
+// @Description Finish the login of a user with two-factor authentication: send the challenge that POST /login answered the password with, and the code of the authenticator app or a recovery code. A challenge is valid for 5 minutes and 5 wrong codes. Wrong codes count as failed logins.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param code body TwoFactorLoginInput true "Challenge and Code"
+// @Success 200 {object} map[string]interface{} "Login successful with the access and refresh tokens"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON or missing challenge or code"
+// @Failure 401 {object} apperror.Response "Unauthorized - challenge invalid or expired, or wrong code"
+// @Failure 403 {object} apperror.Response "Forbidden - account is disabled"
+// @Failure 429 {object} apperror.Response "Too many requests - too many failed logins, wait for Retry-After seconds"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /login/2fa [post]
+func (h *Handler) LoginTwoFactor(c *fiber.Ctx) error {
+	input := new(TwoFactorLoginInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	tokens, err := h.service.LoginTwoFactor(input, c.IP())
+	if err != nil {
+		return retryAfter(c, err)
+	}
+	return loggedIn(c, tokens)
+}
+
+func loggedIn(c *fiber.Ctx, tokens *Tokens) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"message":       i18n.Message(c, "login_successful", "Login successful"),
+	})
+}
+
+// retryAfter says in the Retry-After header how long to wait if err is
+// lockout.ErrTooManyAttempts, and returns err.
+func retryAfter(c *fiber.Ctx, err error) error {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) && errors.Is(err, lockout.ErrTooManyAttempts) {
+		c.Set(fiber.HeaderRetryAfter, appErr.Params["seconds"])
+	}
+	return err
+}
+
+// @Summary Refresh the tokens
+// @Description Swap a refresh token for a new access token and a new refresh token of the same session. Each refresh token works once: using one again ends the session, in case it was stolen.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body RefreshInput true "Refresh Token"
+// @Success 200 {object} Tokens "New tokens"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON or missing refresh token"
+// @Failure 401 {object} apperror.Response "Unauthorized - refresh token invalid, expired or already used"
+// @Failure 403 {object} apperror.Response "Forbidden - account is disabled"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /token/refresh [post]
+func (h *Handler) Refresh(c *fiber.Ctx) error {
+	input := new(RefreshInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	tokens, err := h.service.Refresh(input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(tokens)
+}
+
+// @Summary Logout
+// @Description End the session of the access token. Its access tokens and refresh token stop working at once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Logged out"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /logout [post]
+func (h *Handler) Logout(c *fiber.Ctx) error {
+	sessionID, ok := middleware.SessionID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+	if err := h.service.Logout(sessionID); err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"message": i18n.Message(c, "logged_out", "Logged out successfully"),
+	})
+}
+
+// JWKS serves the public keys access tokens are signed with as a JWK set, so
+// that other services can verify the tokens without asking LegisKuy. It is
+// served at /.well-known/jwks.json, outside the API and its documentation.
+// Clients should cache it for a while and fetch it again when a token names
+// a key they do not have.
+func (h *Handler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.service.JWKS())
+}
+
+// @Summary Set the language of the current user
+// @Description Choose the language, en or id, the API answers the logged-in user in, whatever their Accept-Language header says. An empty language follows the header again.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param language body LanguageInput true "Language"
+// @Success 200 {object} map[string]string "Language updated"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON or unsupported language"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /me/language [put]
+func (h *Handler) SetLanguage(c *fiber.Ctx) error {
+	input := new(LanguageInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.service.SetLanguage(userID, input); err != nil {
+		return err
+	}
+
+	if input.Language != "" {
+		i18n.SetLanguage(c, input.Language)
+	} else {
+		i18n.SetLanguage(c, i18n.Requested(c))
+	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"token":   token,
-		"message": "Login successful",
+		"language": input.Language,
+		"message":  i18n.Message(c, "language_updated", "Language updated successfully"),
 	})
 }
+
+// @Summary Get the two-factor authentication of the current user
+// @Description Get whether two-factor authentication is on, whether the role of the user requires it, and how many recovery codes are left
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} TwoFactorStatus "Two-factor authentication status"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /me/2fa [get]
+func (h *Handler) GetTwoFactor(c *fiber.Ctx) error {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	status, err := h.service.GetTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(status)
+}
+
+// @Summary Set up two-factor authentication
+// @Description Get a new TOTP secret to add to an authenticator app, as text and as an otpauth URI for a QR code. Two-factor authentication is on once the secret is confirmed with POST /me/2fa/confirm; until then, setting up again gives a new secret.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} TwoFactorSetup "Secret to confirm"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 409 {object} apperror.Response "Conflict - two-factor authentication is on already"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /me/2fa [post]
+func (h *Handler) SetUpTwoFactor(c *fiber.Ctx) error {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	setup, err := h.service.SetUpTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(setup)
+}
+
+// @Summary Confirm two-factor authentication
+// @Description Turn two-factor authentication on with a code of the authenticator app the secret was added to. The answer holds recovery codes that each log in once without the app; they are not shown again.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body CodeInput true "Code of the authenticator app"
+// @Success 200 {object} RecoveryCodes "Two-factor authentication is on"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON, missing or wrong code"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 409 {object} apperror.Response "Conflict - two-factor authentication is on already, or was not set up"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /me/2fa/confirm [post]
+func (h *Handler) ConfirmTwoFactor(c *fiber.Ctx) error {
+	input := new(CodeInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	codes, err := h.service.ConfirmTwoFactor(userID, input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(codes)
+}
+
+// @Summary Turn off two-factor authentication
+// @Description Turn two-factor authentication off with a code of the authenticator app or a recovery code. Users whose role requires two-factor authentication cannot turn it off.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body CodeInput true "Code of the authenticator app or recovery code"
+// @Success 200 {object} map[string]string "Two-factor authentication is off"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON, missing or wrong code"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 409 {object} apperror.Response "Conflict - two-factor authentication is off already, or required for the role"
+// @Failure 429 {object} apperror.Response "Too many requests - too many wrong codes, wait for Retry-After seconds"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /me/2fa/disable [post]
+func (h *Handler) DisableTwoFactor(c *fiber.Ctx) error {
+	input := new(CodeInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.service.DisableTwoFactor(userID, c.IP(), input); err != nil {
+		return retryAfter(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"message": i18n.Message(c, "two_factor_disabled", "Two-factor authentication turned off"),
+	})
+}
+
+// @Summary Get new recovery codes
+// @Description Replace the recovery codes of the current user with new ones, with a code of the authenticator app or a recovery code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body CodeInput true "Code of the authenticator app or recovery code"
+// @Success 200 {object} RecoveryCodes "New recovery codes"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON, missing or wrong code"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 409 {object} apperror.Response "Conflict - two-factor authentication is off"
+// @Failure 429 {object} apperror.Response "Too many requests - too many wrong codes, wait for Retry-After seconds"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /me/2fa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	input := new(CodeInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(userID, c.IP(), input)
+	if err != nil {
+		return retryAfter(c, err)
+	}
+	return c.JSON(codes)
+}
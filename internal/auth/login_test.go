@@ -0,0 +1,66 @@
+package auth_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"legiskuy-backend/internal/lockout"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/testserver"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestLoginBurstLimited sends many wrong passwords for one account at the
+// same moment. Only as many as the account may get wrong without waiting are
+// compared; the rest are turned away, however the requests interleave.
+func TestLoginBurstLimited(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		srv.CreateUser("petugas", testserver.Password, rbac.RolePetugas)
+
+		const attempts = 30
+		body, err := json.Marshal(map[string]string{"username": "petugas", "password": "wrong password"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses := make(chan int, attempts)
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// The requests go to the app directly rather than through
+				// srv.Request, which would send them one after the other.
+				req := httptest.NewRequest(http.MethodPost, "/api/v1/login", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				resp, err := srv.App.Test(req, -1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+				statuses <- resp.StatusCode
+			}()
+		}
+		wg.Wait()
+		close(statuses)
+
+		counts := make(map[int]int)
+		for status := range statuses {
+			counts[status]++
+		}
+		// The failure after the free ones is compared too, and makes the
+		// next attempt wait.
+		compared := lockout.DefaultAccountLimit.FreeFailures + 1
+		if counts[http.StatusUnauthorized] != compared || counts[http.StatusTooManyRequests] != attempts-compared {
+			t.Fatalf("got statuses %v, want %d compared and the rest turned away", counts, compared)
+		}
+
+		// The right password has to wait as well.
+		srv.Expect(srv.Request(http.MethodPost, "/api/v1/login", "", map[string]string{
+			"username": "petugas",
+			"password": testserver.Password,
+		}), http.StatusTooManyRequests)
+	})
+}
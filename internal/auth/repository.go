@@ -3,39 +3,86 @@ package auth
 import (
 	"database/sql"
 	"legiskuy-backend/pkg/database"
+	"time"
 )
 
 type Repository interface {
-	Create(user *User) (*User, error)
+	BeginTransaction() (*database.Tx, error)
+	Create(tx *database.Tx, user *User) (*User, error)
 	FindByUsername(username string) (*User, error)
+	FindByID(id int) (*User, error)
+	UpdateLanguage(userID int, language string) error
+
+	CreateSession(tx *database.Tx, session *Session) error
+	FindSession(id string) (*Session, error)
+	RevokeSession(id string, at time.Time) error
+	CreateRefreshToken(tx *database.Tx, token *RefreshToken) error
+	FindRefreshToken(tx *database.Tx, hash string) (*RefreshToken, error)
+	UseRefreshToken(tx *database.Tx, hash string, at time.Time) error
+
+	FindTOTP(userID int) (*TOTPSecret, error)
+	// StartTOTP stores a secret for the user to confirm, in place of one
+	// they have not confirmed. It returns sql.ErrNoRows if they have
+	// confirmed one.
+	StartTOTP(userID int, secret string) error
+	ConfirmTOTP(tx *database.Tx, userID int, step int64, at time.Time) error
+	// UseTOTPStep records that a code of step was used. It returns
+	// sql.ErrNoRows if a code of step or of a later one was used before.
+	UseTOTPStep(tx *database.Tx, userID int, step int64) error
+	// DeleteTOTP turns two-factor authentication off, deleting the secret
+	// and the recovery codes of the user.
+	DeleteTOTP(tx *database.Tx, userID int) error
+	ReplaceRecoveryCodes(tx *database.Tx, userID int, hashes []string) error
+	// UseRecoveryCode returns sql.ErrNoRows if the user has no such code
+	// left.
+	UseRecoveryCode(tx *database.Tx, userID int, hash string, at time.Time) error
+	CountRecoveryCodes(userID int) (int, error)
+
+	CreateChallenge(challenge *LoginChallenge) error
+	FindChallenge(tx *database.Tx, hash string) (*LoginChallenge, error)
+	AddChallengeFailure(tx *database.Tx, hash string) error
+	// UseChallenge returns sql.ErrNoRows if the challenge was used already.
+	UseChallenge(tx *database.Tx, hash string, at time.Time) error
 }
 
 type repository struct {
-	db *sql.DB
+	db *database.Database
 }
 
-func NewRepository() Repository {
+func NewRepository(db *database.Database) Repository {
 	return &repository{
-		db: database.DB,
+		db: db,
 	}
 }
 
-func (r *repository) Create(user *User) (*User, error) {
-	query := `INSERT INTO users (name, username, password, role) VALUES (?, ?, ?, ?)`
-	result, err := r.db.Exec(query, user.Name, user.Username, user.Password, user.Role)
+func (r *repository) BeginTransaction() (*database.Tx, error) {
+	return r.db.Begin()
+}
+
+func (r *repository) Create(tx *database.Tx, user *User) (*User, error) {
+	query := `INSERT INTO users (name, username, password, role, language) VALUES (?, ?, ?, ?, ?)`
+	id, err := tx.Insert(query, user.Name, user.Username, user.Password, user.Role, user.Language)
 	if err != nil {
 		return nil, err
 	}
-	id, _ := result.LastInsertId()
 	user.ID = int(id)
 	return user, nil
 }
 
+const userColumns = `id, name, username, password, role, has_voted, disabled, COALESCE(language, '')`
+
 func (r *repository) FindByUsername(username string) (*User, error) {
-	query := `SELECT id, name, username, password, role, has_voted FROM users WHERE username = ?`
-	row := r.db.QueryRow(query, username)
+	return r.findUser(`SELECT `+userColumns+` FROM users WHERE username = ?`, username)
+}
+
+func (r *repository) FindByID(id int) (*User, error) {
+	return r.findUser(`SELECT `+userColumns+` FROM users WHERE id = ?`, id)
+}
+
+func (r *repository) findUser(query string, args ...interface{}) (*User, error) {
+	row := r.db.QueryRow(query, args...)
 	var u User
-	err := row.Scan(&u.ID, &u.Name, &u.Username, &u.Password, &u.Role, &u.HasVoted)
+	err := row.Scan(&u.ID, &u.Name, &u.Username, &u.Password, &u.Role, &u.HasVoted, &u.Disabled, &u.Language)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -44,3 +91,167 @@ func (r *repository) FindByUsername(username string) (*User, error) {
 	}
 	return &u, nil
 }
+
+func (r *repository) UpdateLanguage(userID int, language string) error {
+	query := `UPDATE users SET language = ? WHERE id = ?`
+	return expectOneRow(r.db.Exec(query, language, userID))
+}
+
+func (r *repository) CreateSession(tx *database.Tx, session *Session) error {
+	query := `INSERT INTO sessions (id, user_id, created_at) VALUES (?, ?, ?)`
+	_, err := tx.Exec(query, session.ID, session.UserID, session.CreatedAt)
+	return err
+}
+
+func (r *repository) FindSession(id string) (*Session, error) {
+	query := `SELECT id, user_id, created_at, revoked_at FROM sessions WHERE id = ?`
+	var s Session
+	err := r.db.QueryRow(query, id).Scan(&s.ID, &s.UserID, &s.CreatedAt, &s.RevokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// RevokeSession ends a session, if it has not ended yet.
+func (r *repository) RevokeSession(id string, at time.Time) error {
+	query := `UPDATE sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+	_, err := r.db.Exec(query, at, id)
+	return err
+}
+
+func (r *repository) CreateRefreshToken(tx *database.Tx, token *RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (token_hash, session_id, expires_at) VALUES (?, ?, ?)`
+	_, err := tx.Exec(query, token.Hash, token.SessionID, token.ExpiresAt)
+	return err
+}
+
+func (r *repository) FindRefreshToken(tx *database.Tx, hash string) (*RefreshToken, error) {
+	query := `SELECT token_hash, session_id, expires_at, used_at FROM refresh_tokens WHERE token_hash = ?` + tx.ForUpdate()
+	var t RefreshToken
+	err := tx.QueryRow(query, hash).Scan(&t.Hash, &t.SessionID, &t.ExpiresAt, &t.UsedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UseRefreshToken marks a refresh token used. It returns sql.ErrNoRows if the
+// token was used already, so that of two requests racing with the same token
+// only one gets through.
+func (r *repository) UseRefreshToken(tx *database.Tx, hash string, at time.Time) error {
+	query := `UPDATE refresh_tokens SET used_at = ? WHERE token_hash = ? AND used_at IS NULL`
+	return expectOneRow(tx.Exec(query, at, hash))
+}
+
+func (r *repository) FindTOTP(userID int) (*TOTPSecret, error) {
+	query := `SELECT user_id, secret, confirmed_at, last_step FROM totp_secrets WHERE user_id = ?`
+	var t TOTPSecret
+	err := r.db.QueryRow(query, userID).Scan(&t.UserID, &t.Secret, &t.ConfirmedAt, &t.LastStep)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *repository) StartTOTP(userID int, secret string) error {
+	query := `INSERT INTO totp_secrets (user_id, secret) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, last_step = 0
+		WHERE totp_secrets.confirmed_at IS NULL`
+	return expectOneRow(r.db.Exec(query, userID, secret))
+}
+
+func (r *repository) ConfirmTOTP(tx *database.Tx, userID int, step int64, at time.Time) error {
+	query := `UPDATE totp_secrets SET confirmed_at = ?, last_step = ? WHERE user_id = ? AND confirmed_at IS NULL`
+	return expectOneRow(tx.Exec(query, at, step, userID))
+}
+
+func (r *repository) UseTOTPStep(tx *database.Tx, userID int, step int64) error {
+	query := `UPDATE totp_secrets SET last_step = ? WHERE user_id = ? AND last_step < ?`
+	return expectOneRow(tx.Exec(query, step, userID, step))
+}
+
+func (r *repository) DeleteTOTP(tx *database.Tx, userID int) error {
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM totp_secrets WHERE user_id = ?`, userID)
+	return err
+}
+
+func (r *repository) ReplaceRecoveryCodes(tx *database.Tx, userID int, hashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if _, err := tx.Exec(`INSERT INTO recovery_codes (code_hash, user_id) VALUES (?, ?)`, hash, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *repository) UseRecoveryCode(tx *database.Tx, userID int, hash string, at time.Time) error {
+	query := `UPDATE recovery_codes SET used_at = ? WHERE code_hash = ? AND user_id = ? AND used_at IS NULL`
+	return expectOneRow(tx.Exec(query, at, hash, userID))
+}
+
+func (r *repository) CountRecoveryCodes(userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM recovery_codes WHERE user_id = ? AND used_at IS NULL`
+	err := r.db.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *repository) CreateChallenge(challenge *LoginChallenge) error {
+	query := `INSERT INTO login_challenges (token_hash, user_id, expires_at) VALUES (?, ?, ?)`
+	_, err := r.db.Exec(query, challenge.Hash, challenge.UserID, challenge.ExpiresAt)
+	return err
+}
+
+func (r *repository) FindChallenge(tx *database.Tx, hash string) (*LoginChallenge, error) {
+	query := `SELECT token_hash, user_id, expires_at, failures, used_at FROM login_challenges WHERE token_hash = ?` + tx.ForUpdate()
+	var c LoginChallenge
+	err := tx.QueryRow(query, hash).Scan(&c.Hash, &c.UserID, &c.ExpiresAt, &c.Failures, &c.UsedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *repository) AddChallengeFailure(tx *database.Tx, hash string) error {
+	query := `UPDATE login_challenges SET failures = failures + 1 WHERE token_hash = ?`
+	_, err := tx.Exec(query, hash)
+	return err
+}
+
+func (r *repository) UseChallenge(tx *database.Tx, hash string, at time.Time) error {
+	query := `UPDATE login_challenges SET used_at = ? WHERE token_hash = ? AND used_at IS NULL`
+	return expectOneRow(tx.Exec(query, at, hash))
+}
+
+func expectOneRow(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
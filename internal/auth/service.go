@@ -1,46 +1,78 @@
 package auth
 
 import (
+	"database/sql"
 	"errors"
+	"legiskuy-backend/internal/lockout"
+	"legiskuy-backend/internal/rbac"
 	"legiskuy-backend/internal/voter"
-	"os"
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/jwtkeys"
+	"legiskuy-backend/pkg/validate"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Service interface {
 	Register(input *RegisterInput) (*UserResponse, error)
-	Login(input *LoginInput) (string, error)
+	// Login starts a session and returns its first tokens, or a challenge
+	// for LoginTwoFactor if the user turned on two-factor authentication.
+	// address is the client address, whose failed logins are counted like
+	// those of the username.
+	Login(input *LoginInput, address string) (*LoginResult, error)
+	// LoginTwoFactor answers the challenge of Login with a code of the
+	// authenticator app or a recovery code, and starts the session.
+	LoginTwoFactor(input *TwoFactorLoginInput, address string) (*Tokens, error)
+	// Refresh swaps a refresh token for new tokens of the same session. A
+	// refresh token that was used before ends the session.
+	Refresh(input *RefreshInput) (*Tokens, error)
+	Logout(sessionID string) error
+	// SessionActive reports whether the access tokens of a session are
+	// still good.
+	SessionActive(sessionID string) (bool, error)
+	// JWKS returns the public keys access tokens can be verified with.
+	JWKS() jwtkeys.JWKSet
+	SetLanguage(userID int, input *LanguageInput) error
+
+	GetTwoFactor(userID int) (*TwoFactorStatus, error)
+	// SetUpTwoFactor gives the user a new secret for their authenticator
+	// app. Two-factor authentication is on once they confirm it with a code.
+	SetUpTwoFactor(userID int) (*TwoFactorSetup, error)
+	ConfirmTwoFactor(userID int, input *CodeInput) (*RecoveryCodes, error)
+	// DisableTwoFactor and RegenerateRecoveryCodes take a code, counted
+	// against address and the user like a login if it is wrong.
+	DisableTwoFactor(userID int, address string, input *CodeInput) error
+	RegenerateRecoveryCodes(userID int, address string, input *CodeInput) (*RecoveryCodes, error)
 }
 
 type service struct {
 	repository Repository
 	voterRepo  voter.Repository
+	lockout    lockout.Service
+	tokens     TokenConfig
+	twoFactor  TwoFactorPolicy
 }
 
-func NewService(repo Repository, voterRepo voter.Repository) Service {
+func NewService(repo Repository, voterRepo voter.Repository, lockoutService lockout.Service, tokens TokenConfig, twoFactor TwoFactorPolicy) Service {
 	return &service{
 		repository: repo,
 		voterRepo:  voterRepo,
+		lockout:    lockoutService,
+		tokens:     tokens.withDefaults(),
+		twoFactor:  twoFactor,
 	}
 }
 
 func (s *service) Register(input *RegisterInput) (*UserResponse, error) {
-	if input.Name == "" {
-		return nil, errors.New("name is required")
-	}
-	if input.Username == "" {
-		return nil, errors.New("username is required")
-	}
-	if input.Password == "" {
-		return nil, errors.New("password is required")
+	if err := validate.Struct(input); err != nil {
+		return nil, err
 	}
 
 	existingUser, _ := s.repository.FindByUsername(input.Username)
 	if existingUser != nil {
-		return nil, errors.New("username already exists")
+		return nil, ErrUsernameTaken
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
@@ -52,56 +84,239 @@ func (s *service) Register(input *RegisterInput) (*UserResponse, error) {
 		Name:     input.Name,
 		Username: input.Username,
 		Password: string(hashedPassword),
-		Role:     "pemilih",
+		Role:     rbac.RolePemilih,
+		Language: input.Language,
 	}
 
-	newUser, err := s.repository.Create(user)
+	tx, err := s.repository.BeginTransaction()
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	newUser, err := s.repository.Create(tx, user)
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, err
+	}
 
 	voterData := &voter.Voter{
 		Name:     newUser.Name,
 		HasVoted: false,
+		UserID:   &newUser.ID,
+	}
+	if _, err := s.voterRepo.CreateForUser(tx, voterData); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
-	s.voterRepo.Create(voterData)
 
 	response := &UserResponse{
 		ID:       newUser.ID,
 		Name:     newUser.Name,
 		Username: newUser.Username,
 		Role:     newUser.Role,
+		Language: newUser.Language,
 	}
 	return response, nil
 }
 
-func (s *service) Login(input *LoginInput) (string, error) {
+// unknownUserHash is compared with the password of a login with an unknown
+// username, so that it takes as long as one with a wrong password.
+const unknownUserHash = "$2a$10$CZghFxm78xWWMkFrvQFDMeD8G9rz.MUYK2I2EheAY8Llw1N51dVPm"
+
+// Login answers a wrong password and an unknown username alike, so that
+// it does not tell which usernames exist.
+func (s *service) Login(input *LoginInput, address string) (*LoginResult, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+
+	attempt := &lockout.Attempt{Username: input.Username, Address: address}
+	if err := s.lockout.Reserve(input.Username, address); err != nil {
+		return nil, s.turnAway(attempt, err)
+	}
+
 	user, err := s.repository.FindByUsername(input.Username)
-	if err != nil || user == nil {
-		return "", errors.New("user not found")
+	if err != nil {
+		return nil, err
+	}
+	hash := unknownUserHash
+	if user != nil {
+		attempt.UserID = &user.ID
+		hash = user.Password
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(input.Password))
+	switch {
+	case user == nil || err != nil:
+		attempt.Result = lockout.ResultInvalidCredentials
+	case user.Disabled:
+		attempt.Result = lockout.ResultAccountDisabled
+	default:
+		attempt.Result = lockout.ResultSucceeded
+		secret, err := s.twoFactorEnabled(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if secret != nil {
+			attempt.Result = lockout.ResultTwoFactorPending
+		}
+	}
+	if err := s.lockout.Record(attempt); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	switch attempt.Result {
+	case lockout.ResultInvalidCredentials:
+		return nil, ErrInvalidCredentials
+	case lockout.ResultAccountDisabled:
+		return nil, apperror.ErrAccountDisabled
+	case lockout.ResultTwoFactorPending:
+		challenge, err := s.startChallenge(user, now)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{Challenge: challenge}, nil
+	}
+
+	session := &Session{ID: randomToken(16), UserID: user.ID, CreatedAt: now}
+
+	tx, err := s.repository.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.repository.CreateSession(tx, session); err != nil {
+		return nil, err
+	}
+	tokens, err := s.issue(tx, user, session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Tokens: tokens}, nil
+}
+
+func (s *service) Refresh(input *RefreshInput) (*Tokens, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
+	now := time.Now().UTC()
+	tx, err := s.repository.BeginTransaction()
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"role":    user.Role,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(), // Token valid for 24 hours
+	token, err := s.repository.FindRefreshToken(tx, hashToken(input.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, ErrInvalidRefresh
+	}
+	if token.UsedAt != nil {
+		return nil, s.endReused(tx, token.SessionID, now)
+	}
+	if now.After(token.ExpiresAt) {
+		return nil, ErrInvalidRefresh
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		return "", errors.New("JWT_SECRET not configured")
+	session, err := s.repository.FindSession(token.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.RevokedAt != nil {
+		return nil, ErrInvalidRefresh
+	}
+	user, err := s.repository.FindByID(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidRefresh
+	}
+	if user.Disabled {
+		return nil, apperror.ErrAccountDisabled
 	}
 
-	t, err := token.SignedString([]byte(jwtSecret))
+	if err := s.repository.UseRefreshToken(tx, token.Hash, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.endReused(tx, token.SessionID, now)
+		}
+		return nil, err
+	}
+	return s.issue(tx, user, session.ID, now)
+}
+
+// endReused ends the session of a refresh token that was used twice and
+// returns the error to answer with. tx is rolled back first: what the
+// request did so far must not be kept.
+func (s *service) endReused(tx *database.Tx, sessionID string, now time.Time) error {
+	tx.Rollback()
+	if err := s.repository.RevokeSession(sessionID, now); err != nil {
+		return err
+	}
+	return ErrRefreshReused
+}
+
+// issue stores a new refresh token of the session, commits tx and returns
+// the tokens.
+func (s *service) issue(tx *database.Tx, user *User, sessionID string, now time.Time) (*Tokens, error) {
+	refresh := randomToken(32)
+	err := s.repository.CreateRefreshToken(tx, &RefreshToken{
+		Hash:      hashToken(refresh),
+		SessionID: sessionID,
+		ExpiresAt: now.Add(s.tokens.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := s.tokens.signAccessToken(user, sessionID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.tokens.AccessTokenTTL / time.Second),
+	}, nil
+}
+
+func (s *service) Logout(sessionID string) error {
+	return s.repository.RevokeSession(sessionID, time.Now().UTC())
+}
+
+func (s *service) SessionActive(sessionID string) (bool, error) {
+	session, err := s.repository.FindSession(sessionID)
 	if err != nil {
-		return "", err
+		return false, err
 	}
+	return session != nil && session.RevokedAt == nil, nil
+}
+
+func (s *service) JWKS() jwtkeys.JWKSet {
+	return s.tokens.Keys.JWKS()
+}
 
-	return t, nil
+func (s *service) SetLanguage(userID int, input *LanguageInput) error {
+	if err := validate.Struct(input); err != nil {
+		return err
+	}
+	err := s.repository.UpdateLanguage(userID, input.Language)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	return err
 }
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"legiskuy-backend/pkg/jwtkeys"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// TokenConfig says how the tokens handed out at login are made.
+type TokenConfig struct {
+	// Keys sign the access tokens.
+	Keys *jwtkeys.KeySet
+	// AccessTokenTTL is how long an access token is valid,
+	// DefaultAccessTokenTTL if zero. Revoking a session stops its access
+	// tokens at once anyway, so it mostly bounds how often clients refresh.
+	AccessTokenTTL time.Duration
+	// RefreshTokenTTL is how long a refresh token can be used,
+	// DefaultRefreshTokenTTL if zero. Each refresh starts the period anew.
+	RefreshTokenTTL time.Duration
+}
+
+func (c TokenConfig) withDefaults() TokenConfig {
+	if c.AccessTokenTTL == 0 {
+		c.AccessTokenTTL = DefaultAccessTokenTTL
+	}
+	if c.RefreshTokenTTL == 0 {
+		c.RefreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	return c
+}
+
+// randomToken returns n random bytes encoded for use in a URL or JSON.
+func randomToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// hashToken is what is stored of a refresh token. The token is random and
+// long enough that a fast hash does not make guessing it feasible.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// signAccessToken returns an access token of user for the session, valid
+// from now.
+func (c TokenConfig) signAccessToken(user *User, sessionID string, now time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"role":    user.Role,
+		"sid":     sessionID,
+		"iat":     now.Unix(),
+		"exp":     now.Add(c.AccessTokenTTL).Unix(),
+	}
+	return c.Keys.Sign(claims)
+}
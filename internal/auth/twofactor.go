@@ -0,0 +1,419 @@
+package auth
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"legiskuy-backend/internal/lockout"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/totp"
+	"legiskuy-backend/pkg/validate"
+	"strings"
+	"time"
+)
+
+// TwoFactorPolicy says whose accounts need two-factor authentication.
+type TwoFactorPolicy struct {
+	// RequireForStaff requires it of every user whose role is not pemilih.
+	// Until they turn it on, they can do nothing else.
+	RequireForStaff bool
+}
+
+// Requires reports whether users with role need two-factor authentication.
+func (p TwoFactorPolicy) Requires(role string) bool {
+	return p.RequireForStaff && role != rbac.RolePemilih
+}
+
+const (
+	// issuer names LegisKuy in authenticator apps.
+	issuer       = "LegisKuy"
+	challengeTTL = 5 * time.Minute
+	// maxChallengeFailures is the number of wrong codes after which a
+	// challenge stops working, and the user has to log in again.
+	maxChallengeFailures = 5
+	recoveryCodeCount    = 10
+	// recoveryCodeAlphabet leaves out letters and digits that are easily
+	// mistaken for each other. Its 32 characters make a 16-character code
+	// 80 bits strong.
+	recoveryCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+	recoveryCodeLength   = 16
+)
+
+// startChallenge stores a new login challenge of user.
+func (s *service) startChallenge(user *User, now time.Time) (*Challenge, error) {
+	challenge := randomToken(32)
+	err := s.repository.CreateChallenge(&LoginChallenge{
+		Hash:      hashToken(challenge),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(challengeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Challenge{Challenge: challenge, ExpiresIn: int(challengeTTL / time.Second)}, nil
+}
+
+func (s *service) LoginTwoFactor(input *TwoFactorLoginInput, address string) (*Tokens, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	// The challenge is looked up once to find whose code to reserve an
+	// attempt for, and again in the transaction that uses it. Reserve runs in
+	// between because it takes the write lock, which SQLite holds for the
+	// whole of a transaction.
+	tx, err := s.repository.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	_, user, err := s.findChallenge(tx, input.Challenge, now)
+	tx.Rollback()
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := &lockout.Attempt{Username: user.Username, UserID: &user.ID, Address: address}
+	if err := s.lockout.Reserve(user.Username, address); err != nil {
+		return nil, s.turnAway(attempt, err)
+	}
+
+	tx, err = s.repository.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	challenge, user, err := s.findChallenge(tx, input.Challenge, now)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.useCode(tx, user.ID, input.Code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.repository.AddChallengeFailure(tx, challenge.Hash); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		attempt.Result = lockout.ResultInvalidCode
+		if err := s.lockout.Record(attempt); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCode
+	}
+
+	if err := s.repository.UseChallenge(tx, challenge.Hash, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidChallenge
+		}
+		return nil, err
+	}
+	session := &Session{ID: randomToken(16), UserID: user.ID, CreatedAt: now}
+	if err := s.repository.CreateSession(tx, session); err != nil {
+		return nil, err
+	}
+	tokens, err := s.issue(tx, user, session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt.Result = lockout.ResultSucceeded
+	if err := s.lockout.Record(attempt); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// findChallenge returns the challenge of the given token and its user, or an
+// error if it cannot be answered.
+func (s *service) findChallenge(tx *database.Tx, token string, now time.Time) (*LoginChallenge, *User, error) {
+	challenge, err := s.repository.FindChallenge(tx, hashToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge == nil || challenge.UsedAt != nil || now.After(challenge.ExpiresAt) || challenge.Failures >= maxChallengeFailures {
+		return nil, nil, ErrInvalidChallenge
+	}
+	user, err := s.repository.FindByID(challenge.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrInvalidChallenge
+	}
+	if user.Disabled {
+		return nil, nil, apperror.ErrAccountDisabled
+	}
+	return challenge, user, nil
+}
+
+// turnAway records an attempt the lockout turned away with err, and returns
+// err.
+func (s *service) turnAway(attempt *lockout.Attempt, err error) error {
+	if !errors.Is(err, lockout.ErrTooManyAttempts) {
+		return err
+	}
+	attempt.Result = lockout.ResultLockedOut
+	if recordErr := s.lockout.Record(attempt); recordErr != nil {
+		return recordErr
+	}
+	return err
+}
+
+// useCode reports whether code is a code of the authenticator app of the
+// user or one of their recovery codes, and uses it up if so.
+func (s *service) useCode(tx *database.Tx, userID int, code string, now time.Time) (bool, error) {
+	secret, err := s.repository.FindTOTP(userID)
+	if err != nil {
+		return false, err
+	}
+	if secret == nil || secret.ConfirmedAt == nil {
+		return false, nil
+	}
+
+	if step, ok := totp.Validate(secret.Secret, code, now); ok {
+		err = s.repository.UseTOTPStep(tx, userID, step)
+	} else {
+		err = s.repository.UseRecoveryCode(tx, userID, hashToken(normalizeRecoveryCode(code)), now)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// withCode runs fn in a transaction if code is a good code of user, see
+// useCode. A wrong code counts against the user like a failed login, so that
+// whoever holds their token cannot guess it either.
+func (s *service) withCode(user *User, address, code string, fn func(tx *database.Tx) error) error {
+	attempt := &lockout.Attempt{Username: user.Username, UserID: &user.ID, Address: address}
+	if err := s.lockout.Reserve(user.Username, address); err != nil {
+		return s.turnAway(attempt, err)
+	}
+
+	tx, err := s.repository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ok, err := s.useCode(tx, user.ID, code, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		tx.Rollback()
+		attempt.Result = lockout.ResultInvalidCode
+		if err := s.lockout.Record(attempt); err != nil {
+			return err
+		}
+		return ErrWrongCode
+	}
+	err = fn(tx)
+	if err == nil {
+		err = tx.Commit()
+	}
+	tx.Rollback()
+
+	// The code was right, so the attempt reserved for it was no failure.
+	// Release takes the write lock, so it waits for tx to end.
+	if releaseErr := s.lockout.Release(user.Username, address); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+// findUser returns the logged-in user.
+func (s *service) findUser(userID int) (*User, error) {
+	user, err := s.repository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// twoFactorEnabled returns the confirmed secret of the user, nil if they
+// have not turned two-factor authentication on.
+func (s *service) twoFactorEnabled(userID int) (*TOTPSecret, error) {
+	secret, err := s.repository.FindTOTP(userID)
+	if err != nil || secret == nil || secret.ConfirmedAt == nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (s *service) GetTwoFactor(userID int) (*TwoFactorStatus, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.twoFactorEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &TwoFactorStatus{Enabled: secret != nil, Required: s.twoFactor.Requires(user.Role)}
+	if status.Enabled {
+		if status.RecoveryCodesLeft, err = s.repository.CountRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (s *service) SetUpTwoFactor(userID int) (*TwoFactorSetup, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.StartTOTP(userID, secret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTwoFactorEnabled
+		}
+		return nil, err
+	}
+	return &TwoFactorSetup{Secret: secret, URI: totp.URI(issuer, user.Username, secret)}, nil
+}
+
+func (s *service) ConfirmTwoFactor(userID int, input *CodeInput) (*RecoveryCodes, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	secret, err := s.repository.FindTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, ErrNoTwoFactorSetup
+	}
+	if secret.ConfirmedAt != nil {
+		return nil, ErrTwoFactorEnabled
+	}
+	now := time.Now().UTC()
+	step, ok := totp.Validate(secret.Secret, input.Code, now)
+	if !ok {
+		return nil, ErrWrongCode
+	}
+
+	tx, err := s.repository.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.repository.ConfirmTOTP(tx, userID, step, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTwoFactorEnabled
+		}
+		return nil, err
+	}
+	codes, err := s.replaceRecoveryCodes(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *service) DisableTwoFactor(userID int, address string, input *CodeInput) error {
+	if err := validate.Struct(input); err != nil {
+		return err
+	}
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	if s.twoFactor.Requires(user.Role) {
+		return ErrTwoFactorRequired
+	}
+	secret, err := s.twoFactorEnabled(userID)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return ErrTwoFactorDisabled
+	}
+	return s.withCode(user, address, input.Code, func(tx *database.Tx) error {
+		return s.repository.DeleteTOTP(tx, userID)
+	})
+}
+
+func (s *service) RegenerateRecoveryCodes(userID int, address string, input *CodeInput) (*RecoveryCodes, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.twoFactorEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, ErrTwoFactorDisabled
+	}
+
+	var codes *RecoveryCodes
+	err = s.withCode(user, address, input.Code, func(tx *database.Tx) error {
+		codes, err = s.replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// replaceRecoveryCodes gives the user new recovery codes in place of those
+// they had.
+func (s *service) replaceRecoveryCodes(tx *database.Tx, userID int) (*RecoveryCodes, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i] = newRecoveryCode()
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+	if err := s.repository.ReplaceRecoveryCodes(tx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return &RecoveryCodes{Codes: codes}, nil
+}
+
+// newRecoveryCode returns a random code in groups of four characters, such
+// as abcd-efgh-ijkm-npqr.
+func newRecoveryCode() string {
+	b := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	var code strings.Builder
+	for i, c := range b {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(recoveryCodeAlphabet[int(c)%len(recoveryCodeAlphabet)])
+	}
+	return code.String()
+}
+
+// normalizeRecoveryCode lets users type a recovery code without dashes, with
+// spaces or in capitals.
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+}
@@ -0,0 +1,54 @@
+package auth_test
+
+import (
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/testserver"
+	"legiskuy-backend/pkg/totp"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestTwoFactorCodeOnce checks that a code of the authenticator app logs in
+// only once, including the code that turned two-factor authentication on.
+func TestTwoFactorCodeOnce(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+
+		var setup struct {
+			Secret string `json:"secret"`
+		}
+		srv.Decode(srv.Request(http.MethodPost, "/api/v1/me/2fa", token, nil), &setup)
+		code := func(step int64) string {
+			t.Helper()
+			c, err := totp.Code(setup.Secret, step)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return c
+		}
+		// The next steps are still inside the window the server accepts
+		// as long as the test takes less than a step.
+		step := totp.Step(time.Now())
+		srv.Expect(srv.Request(http.MethodPost, "/api/v1/me/2fa/confirm", token, map[string]string{"code": code(step)}), http.StatusOK)
+
+		login := func(code string) *http.Response {
+			t.Helper()
+			var challenge struct {
+				Challenge string `json:"challenge"`
+			}
+			srv.Decode(srv.Request(http.MethodPost, "/api/v1/login", "", map[string]string{
+				"username": "petugas",
+				"password": testserver.Password,
+			}), &challenge)
+			return srv.Request(http.MethodPost, "/api/v1/login/2fa", "", map[string]string{
+				"challenge": challenge.Challenge,
+				"code":      code,
+			})
+		}
+		srv.Expect(login(code(step)), http.StatusUnauthorized)
+		srv.Expect(login(code(step+1)), http.StatusOK)
+		srv.Expect(login(code(step+1)), http.StatusUnauthorized)
+		srv.Expect(login(code(step)), http.StatusUnauthorized)
+	})
+}
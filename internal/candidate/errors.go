@@ -0,0 +1,10 @@
+package candidate
+
+import "legiskuy-backend/pkg/apperror"
+
+var (
+	ErrInvalidID         = apperror.InvalidID("candidate")
+	ErrDistrictNotFound  = apperror.Field("district_id", "not_found", "district not found")
+	ErrPartyNotFound     = apperror.Field("party_id", "not_found", "party not found")
+	ErrCandidateNotFound = apperror.NotFound("candidate_not_found", "Candidate not found")
+)
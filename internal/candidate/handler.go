@@ -1,7 +1,8 @@
 package candidate
 
 import (
-	"database/sql"
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/i18n"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -18,32 +19,25 @@ func NewHandler(service Service) *Handler {
 }
 
 // @Summary Create a new candidate
-// @Description Create a new candidate with the provided name and party
+// @Description Create a new candidate with the provided name and party in an election
 // @Tags candidate
 // @Accept json
 // @Produce json
 // @Param candidate body CreateCandidateInput true "Candidate Data"
 // @Success 201 {object} map[string]interface{} "Candidate created successfully"
-// @Failure 400 {object} map[string]string "Bad request - cannot parse JSON or missing required fields"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON, missing required fields, district or party not found"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 409 {object} apperror.Response "Conflict - election is already open"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /candidates [post]
 func (h *Handler) CreateCandidate(c *fiber.Ctx) error {
 	input := new(CreateCandidateInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
+		return apperror.ErrInvalidJSON
 	}
 	candidate, err := h.service.CreateCandidate(input)
 	if err != nil {
-		if err.Error() == "name is required" || err.Error() == "party is required" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create candidate",
-		})
+		return err
 	}
 	return c.Status(fiber.StatusCreated).JSON(candidate)
 }
@@ -53,25 +47,29 @@ func (h *Handler) CreateCandidate(c *fiber.Ctx) error {
 // @Tags candidate
 // @Accept json
 // @Produce json
+// @Param election_id query int false "Filter candidates by election ID"
+// @Param district_id query int false "Filter candidates by district ID"
 // @Param name query string false "Filter candidates by name"
-// @Param party query string false "Filter candidates by party"
+// @Param party_id query int false "Filter candidates by party ID"
+// @Param party query string false "Filter candidates by party name or abbreviation"
 // @Param sort_by query string false "Sort by field (name, party, vote_count)"
 // @Param order query string false "Sort order (asc, desc)"
 // @Success 200 {array} map[string]interface{} "List of candidates"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /candidates [get]
 func (h *Handler) GetAllCandidates(c *fiber.Ctx) error {
+	electionID := c.QueryInt("election_id")
+	districtID := c.QueryInt("district_id")
 	name := c.Query("name")
+	partyID := c.QueryInt("party_id")
 	party := c.Query("party")
 
 	sortBy := c.Query("sort_by")
 	order := c.Query("order")
 
-	candidates, err := h.service.GetAllCandidates(name, party, sortBy, order)
+	candidates, err := h.service.GetAllCandidates(electionID, districtID, partyID, name, party, sortBy, order)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get candidates",
-		})
+		return err
 	}
 	return c.JSON(candidates)
 }
@@ -83,29 +81,19 @@ func (h *Handler) GetAllCandidates(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path int true "Candidate ID"
 // @Success 200 {object} map[string]interface{} "Candidate details"
-// @Failure 400 {object} map[string]string "Bad request - invalid candidate ID"
-// @Failure 404 {object} map[string]string "Not found - candidate not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - invalid candidate ID"
+// @Failure 404 {object} apperror.Response "Not found - candidate not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /candidates/{id} [get]
 func (h *Handler) GetCandidateByID(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid candidate ID",
-		})
+		return ErrInvalidID
 	}
 	candidate, err := h.service.GetCandidateByID(id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get candidate",
-		})
+		return err
 	}
-	if candidate == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Candidate not found",
-		})
-	}
-
 	return c.JSON(candidate)
 }
 
@@ -117,46 +105,25 @@ func (h *Handler) GetCandidateByID(c *fiber.Ctx) error {
 // @Param id path int true "Candidate ID"
 // @Param candidate body UpdateCandidateInput true "Updated candidate data"
 // @Success 200 {object} map[string]interface{} "Updated candidate details"
-// @Failure 400 {object} map[string]string "Bad request - invalid candidate ID or cannot parse JSON"
-// @Failure 404 {object} map[string]string "Not found - candidate not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - invalid candidate ID, cannot parse JSON, district or party not found"
+// @Failure 404 {object} apperror.Response "Not found - candidate not found"
+// @Failure 409 {object} apperror.Response "Conflict - election is already open"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /candidates/{id} [put]
 func (h *Handler) UpdateCandidate(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid candidate ID",
-		})
+		return ErrInvalidID
 	}
 
 	input := new(UpdateCandidateInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
+		return apperror.ErrInvalidJSON
 	}
 
 	candidate, err := h.service.UpdateCandidate(id, input)
 	if err != nil {
-		if err.Error() == "name is required" || err.Error() == "party is required" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		if err == sql.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Candidate not found",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update candidate",
-		})
-	}
-
-	if candidate == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Candidate not found",
-		})
+		return err
 	}
 
 	return c.JSON(candidate)
@@ -169,31 +136,23 @@ func (h *Handler) UpdateCandidate(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path int true "Candidate ID"
 // @Success 200 {object} map[string]string "Candidate deleted successfully"
-// @Failure 400 {object} map[string]string "Bad request - invalid candidate ID"
-// @Failure 404 {object} map[string]string "Not found - candidate not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - invalid candidate ID"
+// @Failure 404 {object} apperror.Response "Not found - candidate not found"
+// @Failure 409 {object} apperror.Response "Conflict - election is already open"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /candidates/{id} [delete]
 func (h *Handler) DeleteCandidate(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid candidate ID",
-		})
+		return ErrInvalidID
 	}
 
 	err = h.service.DeleteCandidate(id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Candidate not found",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete candidate",
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Candidate deleted successfully",
+		"message": i18n.Message(c, "candidate_deleted", "Candidate deleted successfully"),
 	})
 }
@@ -2,62 +2,82 @@ package candidate
 
 import (
 	"database/sql"
+	"legiskuy-backend/internal/party"
 	"legiskuy-backend/pkg/database"
 )
 
 type Candidate struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	Party string `json:"party"`
-	Votes int    `json:"votes"`
+	ID         int          `json:"id"`
+	ElectionID int          `json:"election_id"`
+	DistrictID *int         `json:"district_id"`
+	Name       string       `json:"name"`
+	PartyID    int          `json:"party_id"`
+	Party      *party.Party `json:"party"`
+	Votes      int          `json:"votes"`
 }
 
 type Repository interface {
 	Create(candidate *Candidate) (int64, error)
-	FindAll(name, party string) ([]Candidate, error)
+	FindAll(electionID, districtID, partyID int, name, party string) ([]Candidate, error)
 	FindByID(id int) (*Candidate, error)
 	Update(id int, candidate *Candidate) error
 	Delete(id int) error
-	IncrementVoteCount(tx *sql.Tx, candidateID int) error
+	AddVotes(tx *database.Tx, candidateID, votes int) error
+	SetVoteCount(tx *database.Tx, candidateID, votes int) error
 }
 
 type repository struct {
-	db *sql.DB
+	db *database.Database
 }
 
-func NewRepository() Repository {
+func NewRepository(db *database.Database) Repository {
 	return &repository{
-		db: database.DB,
+		db: db,
 	}
 }
 
 func (r *repository) Create(candidate *Candidate) (int64, error) {
-	query := `INSERT INTO candidates (name, party) VALUES (?, ?)`
-	result, err := r.db.Exec(query, candidate.Name, candidate.Party)
-	if err != nil {
-		return 0, err
-	}
-	id, err := result.LastInsertId()
+	query := `INSERT INTO candidates (election_id, district_id, name, party_id) VALUES (?, ?, ?, ?)`
+	id, err := r.db.Insert(query, candidate.ElectionID, candidate.DistrictID, candidate.Name, candidate.PartyID)
 	if err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
-func (r *repository) FindAll(name, party string) ([]Candidate, error) {
-	query := `SELECT id, name, party, votes FROM candidates WHERE 1=1`
+const selectCandidate = `SELECT c.id, c.election_id, c.district_id, c.name, c.votes, p.id, p.name, p.abbreviation, p.ballot_number, p.logo_url, p.color FROM candidates c JOIN parties p ON p.id = c.party_id`
+
+func (r *repository) FindAll(electionID, districtID, partyID int, name, party string) ([]Candidate, error) {
+	query := selectCandidate + ` WHERE 1=1`
 	args := []interface{}{}
 
+	if electionID != 0 {
+		query += " AND c.election_id = ?"
+		args = append(args, electionID)
+	}
+
+	if districtID != 0 {
+		query += " AND c.district_id = ?"
+		args = append(args, districtID)
+	}
+
+	if partyID != 0 {
+		query += " AND c.party_id = ?"
+		args = append(args, partyID)
+	}
+
 	if name != "" {
-		query += " AND name LIKE ?"
+		query += " AND LOWER(c.name) LIKE LOWER(?)"
 		args = append(args, "%"+name+"%")
 	}
 
 	if party != "" {
-		query += " AND party LIKE ?"
-		args = append(args, "%"+party+"%")
+		query += " AND (LOWER(p.name) LIKE LOWER(?) OR LOWER(p.abbreviation) LIKE LOWER(?))"
+		args = append(args, "%"+party+"%", "%"+party+"%")
 	}
 
+	query += " ORDER BY c.id"
+
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -66,32 +86,30 @@ func (r *repository) FindAll(name, party string) ([]Candidate, error) {
 
 	candidates := make([]Candidate, 0)
 	for rows.Next() {
-		var c Candidate
-		if err := rows.Scan(&c.ID, &c.Name, &c.Party, &c.Votes); err != nil {
+		c, err := scanCandidate(rows)
+		if err != nil {
 			return nil, err
 		}
-		candidates = append(candidates, c)
+		candidates = append(candidates, *c)
 	}
 	return candidates, nil
 }
 
 func (r *repository) FindByID(id int) (*Candidate, error) {
-	query := `SELECT id, name, party, votes FROM candidates WHERE id = ?`
-	row := r.db.QueryRow(query, id)
-
-	var c Candidate
-	if err := row.Scan(&c.ID, &c.Name, &c.Party, &c.Votes); err != nil {
+	query := selectCandidate + ` WHERE c.id = ?`
+	c, err := scanCandidate(r.db.QueryRow(query, id))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &c, nil
+	return c, nil
 }
 
 func (r *repository) Update(id int, candidate *Candidate) error {
-	query := `UPDATE candidates SET name = ?, party = ? WHERE id = ?`
-	result, err := r.db.Exec(query, candidate.Name, candidate.Party, id)
+	query := `UPDATE candidates SET district_id = ?, name = ?, party_id = ? WHERE id = ?`
+	result, err := r.db.Exec(query, candidate.DistrictID, candidate.Name, candidate.PartyID, id)
 	if err != nil {
 		return err
 	}
@@ -127,8 +145,34 @@ func (r *repository) Delete(id int) error {
 	return nil
 }
 
-func (r *repository) IncrementVoteCount(tx *sql.Tx, candidateID int) error {
-	query := `UPDATE candidates SET votes = votes + 1 WHERE id = ?`
-	_, err := tx.Exec(query, candidateID)
+func (r *repository) AddVotes(tx *database.Tx, candidateID, votes int) error {
+	query := `UPDATE candidates SET votes = votes + ? WHERE id = ?`
+	_, err := tx.Exec(query, votes, candidateID)
+	return err
+}
+
+func (r *repository) SetVoteCount(tx *database.Tx, candidateID, votes int) error {
+	query := `UPDATE candidates SET votes = ? WHERE id = ?`
+	_, err := tx.Exec(query, votes, candidateID)
 	return err
 }
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCandidate(row scanner) (*Candidate, error) {
+	var c Candidate
+	var p party.Party
+	var districtID sql.NullInt64
+	if err := row.Scan(&c.ID, &c.ElectionID, &districtID, &c.Name, &c.Votes, &p.ID, &p.Name, &p.Abbreviation, &p.BallotNumber, &p.LogoURL, &p.Color); err != nil {
+		return nil, err
+	}
+	c.PartyID = p.ID
+	c.Party = &p
+	if districtID.Valid {
+		id := int(districtID.Int64)
+		c.DistrictID = &id
+	}
+	return &c, nil
+}
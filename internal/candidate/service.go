@@ -1,49 +1,81 @@
 package candidate
 
 import (
+	"database/sql"
 	"errors"
+	"legiskuy-backend/internal/district"
+	"legiskuy-backend/internal/party"
+	"legiskuy-backend/pkg/validate"
 	"strings"
 )
 
 type Service interface {
 	CreateCandidate(input *CreateCandidateInput) (*Candidate, error)
-	GetAllCandidates(name, party, sortBy, order string) ([]Candidate, error)
+	GetAllCandidates(electionID, districtID, partyID int, name, party, sortBy, order string) ([]Candidate, error)
 	GetCandidateByID(id int) (*Candidate, error)
 	UpdateCandidate(id int, input *UpdateCandidateInput) (*Candidate, error)
 	DeleteCandidate(id int) error
 }
 
+// ElectionGuard is implemented by the election service so candidate changes
+// can be checked against their election without importing that package.
+type ElectionGuard interface {
+	CheckCandidateChanges(electionID int) error
+}
+
 type service struct {
-	repository Repository
+	repository   Repository
+	elections    ElectionGuard
+	districtRepo district.Repository
+	partyRepo    party.Repository
 }
 
-func NewService(repo Repository) Service {
+func NewService(repo Repository, elections ElectionGuard, districtRepo district.Repository, partyRepo party.Repository) Service {
 	return &service{
-		repository: repo,
+		repository:   repo,
+		elections:    elections,
+		districtRepo: districtRepo,
+		partyRepo:    partyRepo,
 	}
 }
 
 type CreateCandidateInput struct {
-	Name  string `json:"name"`
-	Party string `json:"party"`
+	ElectionID int    `json:"election_id" validate:"required"`
+	DistrictID *int   `json:"district_id"`
+	Name       string `json:"name" validate:"trim,required,max=100"`
+	PartyID    int    `json:"party_id" validate:"required"`
 }
 
 type UpdateCandidateInput struct {
-	Name  string `json:"name"`
-	Party string `json:"party"`
+	DistrictID *int   `json:"district_id"`
+	Name       string `json:"name" validate:"trim,required,max=100"`
+	PartyID    int    `json:"party_id" validate:"required"`
 }
 
 func (s *service) CreateCandidate(input *CreateCandidateInput) (*Candidate, error) {
-	if input.Name == "" {
-		return nil, errors.New("name is required")
+	if err := validate.Struct(input); err != nil {
+		return nil, err
 	}
-	if input.Party == "" {
-		return nil, errors.New("party is required")
+
+	if err := s.checkDistrict(input.DistrictID); err != nil {
+		return nil, err
+	}
+
+	p, err := s.findParty(input.PartyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.elections.CheckCandidateChanges(input.ElectionID); err != nil {
+		return nil, err
 	}
 
 	candidate := &Candidate{
-		Name:  input.Name,
-		Party: input.Party,
+		ElectionID: input.ElectionID,
+		DistrictID: input.DistrictID,
+		Name:       input.Name,
+		PartyID:    p.ID,
+		Party:      p,
 	}
 
 	id, err := s.repository.Create(candidate)
@@ -55,8 +87,8 @@ func (s *service) CreateCandidate(input *CreateCandidateInput) (*Candidate, erro
 	return candidate, nil
 }
 
-func (s *service) GetAllCandidates(name, party, sortBy, order string) ([]Candidate, error) {
-	candidates, err := s.repository.FindAll(name, party)
+func (s *service) GetAllCandidates(electionID, districtID, partyID int, name, party, sortBy, order string) ([]Candidate, error) {
+	candidates, err := s.repository.FindAll(electionID, districtID, partyID, name, party)
 	if err != nil {
 		return nil, err
 	}
@@ -81,23 +113,48 @@ func (s *service) GetAllCandidates(name, party, sortBy, order string) ([]Candida
 }
 
 func (s *service) GetCandidateByID(id int) (*Candidate, error) {
-	return s.repository.FindByID(id)
+	candidate, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if candidate == nil {
+		return nil, ErrCandidateNotFound
+	}
+	return candidate, nil
 }
 
 func (s *service) UpdateCandidate(id int, input *UpdateCandidateInput) (*Candidate, error) {
-	if input.Name == "" {
-		return nil, errors.New("name is required")
+	if err := validate.Struct(input); err != nil {
+		return nil, err
 	}
-	if input.Party == "" {
-		return nil, errors.New("party is required")
+
+	if err := s.checkDistrict(input.DistrictID); err != nil {
+		return nil, err
+	}
+
+	p, err := s.findParty(input.PartyID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findForChange(id)
+	if err != nil {
+		return nil, err
 	}
 
 	candidateToUpdate := &Candidate{
-		Name:  input.Name,
-		Party: input.Party,
+		ElectionID: existing.ElectionID,
+		DistrictID: input.DistrictID,
+		Name:       input.Name,
+		PartyID:    p.ID,
+		Party:      p,
+		Votes:      existing.Votes,
 	}
 
-	err := s.repository.Update(id, candidateToUpdate)
+	err = s.repository.Update(id, candidateToUpdate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCandidateNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +164,53 @@ func (s *service) UpdateCandidate(id int, input *UpdateCandidateInput) (*Candida
 }
 
 func (s *service) DeleteCandidate(id int) error {
-	return s.repository.Delete(id)
+	if _, err := s.findForChange(id); err != nil {
+		return err
+	}
+	err := s.repository.Delete(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCandidateNotFound
+	}
+	return err
+}
+
+func (s *service) findForChange(id int) (*Candidate, error) {
+	candidate, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if candidate == nil {
+		return nil, ErrCandidateNotFound
+	}
+	if err := s.elections.CheckCandidateChanges(candidate.ElectionID); err != nil {
+		return nil, err
+	}
+	return candidate, nil
+}
+
+func (s *service) checkDistrict(districtID *int) error {
+	if districtID == nil {
+		return nil
+	}
+	d, err := s.districtRepo.FindByID(*districtID)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return ErrDistrictNotFound
+	}
+	return nil
+}
+
+func (s *service) findParty(id int) (*party.Party, error) {
+	p, err := s.partyRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPartyNotFound
+	}
+	return p, nil
 }
 
 func selectionSort(arr []Candidate, by string, descending bool) {
@@ -143,9 +246,9 @@ func insertionSort(arr []Candidate, by string, descending bool) {
 			switch by {
 			case "party":
 				if descending {
-					shouldMove = arr[j].Party < key.Party
+					shouldMove = arr[j].Party.Name < key.Party.Name
 				} else {
-					shouldMove = arr[j].Party > key.Party
+					shouldMove = arr[j].Party.Name > key.Party.Name
 				}
 			case "votes":
 				if descending {
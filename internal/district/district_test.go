@@ -0,0 +1,26 @@
+package district_test
+
+import (
+	"fmt"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/testserver"
+	"net/http"
+	"testing"
+)
+
+// TestDistrictLockedAfterClose checks that the seats of a district cannot
+// change under the results of a closed election.
+func TestDistrictLockedAfterClose(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		e := srv.OpenElection(token)
+
+		path := fmt.Sprintf("/api/v1/districts/%d", e.DistrictID)
+		update := map[string]interface{}{"name": "Dapil 1", "seats": 3}
+		srv.Expect(srv.Request(http.MethodPut, path, token, update), http.StatusOK)
+
+		srv.Transition(token, e.ID, "closed")
+		srv.Expect(srv.Request(http.MethodPut, path, token, update), http.StatusConflict)
+		srv.Expect(srv.Request(http.MethodDelete, path, token, nil), http.StatusConflict)
+	})
+}
@@ -0,0 +1,11 @@
+package district
+
+import "legiskuy-backend/pkg/apperror"
+
+var (
+	ErrInvalidID        = apperror.InvalidID("district")
+	ErrDistrictNotFound = apperror.NotFound("district_not_found", "District not found")
+	ErrNameTaken        = apperror.Conflict("district_name_taken", "District name already exists")
+	ErrDistrictInUse    = apperror.Conflict("district_in_use", "district is still assigned to candidates or voters")
+	ErrDistrictLocked   = apperror.Conflict("district_locked", "district cannot be changed once an election it takes part in is closed")
+)
@@ -0,0 +1,143 @@
+package district
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/i18n"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// @Summary Create a new district
+// @Description Create a new electoral district (dapil) with its seat count
+// @Tags district
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param district body CreateDistrictInput true "District Data"
+// @Success 201 {object} District "District created successfully"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON or invalid district data"
+// @Failure 409 {object} apperror.Response "Conflict - district name already exists"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /districts [post]
+func (h *Handler) CreateDistrict(c *fiber.Ctx) error {
+	input := new(CreateDistrictInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	district, err := h.service.CreateDistrict(input)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(district)
+}
+
+// @Summary Get all districts
+// @Description Get all electoral districts
+// @Tags district
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} District "List of districts"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /districts [get]
+func (h *Handler) GetAllDistricts(c *fiber.Ctx) error {
+	districts, err := h.service.GetAllDistricts()
+	if err != nil {
+		return err
+	}
+	return c.JSON(districts)
+}
+
+// @Summary Get district by ID
+// @Description Get a specific electoral district by its ID
+// @Tags district
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "District ID"
+// @Success 200 {object} District "District details"
+// @Failure 400 {object} apperror.Response "Bad request - invalid district ID"
+// @Failure 404 {object} apperror.Response "Not found - district not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /districts/{id} [get]
+func (h *Handler) GetDistrictByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+	district, err := h.service.GetDistrictByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(district)
+}
+
+// @Summary Update district
+// @Description Update an electoral district's name and seat count. A district cannot be changed once an election with candidates in it is closed.
+// @Tags district
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "District ID"
+// @Param district body UpdateDistrictInput true "Updated district data"
+// @Success 200 {object} District "Updated district details"
+// @Failure 400 {object} apperror.Response "Bad request - invalid district ID, cannot parse JSON or invalid district data"
+// @Failure 404 {object} apperror.Response "Not found - district not found"
+// @Failure 409 {object} apperror.Response "Conflict - district name already exists, or a closed election uses the district"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /districts/{id} [put]
+func (h *Handler) UpdateDistrict(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+	input := new(UpdateDistrictInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	district, err := h.service.UpdateDistrict(id, input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(district)
+}
+
+// @Summary Delete district
+// @Description Delete an electoral district that has no candidates or voters assigned
+// @Tags district
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "District ID"
+// @Success 200 {object} map[string]string "District deleted successfully"
+// @Failure 400 {object} apperror.Response "Bad request - invalid district ID"
+// @Failure 404 {object} apperror.Response "Not found - district not found"
+// @Failure 409 {object} apperror.Response "Conflict - district is still in use, or a closed election uses it"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /districts/{id} [delete]
+func (h *Handler) DeleteDistrict(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	if err := h.service.DeleteDistrict(id); err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": i18n.Message(c, "district_deleted", "District deleted successfully"),
+	})
+}
@@ -0,0 +1,130 @@
+package district
+
+import (
+	"database/sql"
+	"legiskuy-backend/pkg/database"
+)
+
+type District struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Seats int    `json:"seats"`
+}
+
+type Repository interface {
+	Create(district *District) (int64, error)
+	FindAll() ([]District, error)
+	FindByID(id int) (*District, error)
+	Update(id int, district *District) error
+	Delete(id int) error
+	IsInUse(id int) (bool, error)
+	IsLocked(id int) (bool, error)
+}
+
+type repository struct {
+	db *database.Database
+}
+
+func NewRepository(db *database.Database) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(district *District) (int64, error) {
+	query := `INSERT INTO districts (name, seats) VALUES (?, ?)`
+	id, err := r.db.Insert(query, district.Name, district.Seats)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *repository) FindAll() ([]District, error) {
+	query := `SELECT id, name, seats FROM districts ORDER BY id`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	districts := make([]District, 0)
+	for rows.Next() {
+		var d District
+		if err := rows.Scan(&d.ID, &d.Name, &d.Seats); err != nil {
+			return nil, err
+		}
+		districts = append(districts, d)
+	}
+	return districts, nil
+}
+
+func (r *repository) FindByID(id int) (*District, error) {
+	query := `SELECT id, name, seats FROM districts WHERE id = ?`
+	row := r.db.QueryRow(query, id)
+
+	var d District
+	if err := row.Scan(&d.ID, &d.Name, &d.Seats); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *repository) Update(id int, district *District) error {
+	query := `UPDATE districts SET name = ?, seats = ? WHERE id = ?`
+	result, err := r.db.Exec(query, district.Name, district.Seats, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) Delete(id int) error {
+	query := `DELETE FROM districts WHERE id = ?`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) IsInUse(id int) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM candidates WHERE district_id = ?) OR EXISTS (SELECT 1 FROM voters WHERE district_id = ?)`
+	var inUse bool
+	if err := r.db.QueryRow(query, id, id).Scan(&inUse); err != nil {
+		return false, err
+	}
+	return inUse, nil
+}
+
+// IsLocked reports whether a closed or certified election has candidates in
+// the district. Its results, and the seats they are allocated, depend on the
+// district as it is.
+func (r *repository) IsLocked(id int) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM candidates c JOIN elections e ON e.id = c.election_id
+		WHERE c.district_id = ? AND e.status IN ('closed', 'certified')
+	)`
+	var locked bool
+	if err := r.db.QueryRow(query, id).Scan(&locked); err != nil {
+		return false, err
+	}
+	return locked, nil
+}
@@ -0,0 +1,128 @@
+package district
+
+import (
+	"database/sql"
+	"errors"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/validate"
+)
+
+type Service interface {
+	CreateDistrict(input *CreateDistrictInput) (*District, error)
+	GetAllDistricts() ([]District, error)
+	GetDistrictByID(id int) (*District, error)
+	UpdateDistrict(id int, input *UpdateDistrictInput) (*District, error)
+	DeleteDistrict(id int) error
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{
+		repository: repo,
+	}
+}
+
+type CreateDistrictInput struct {
+	Name  string `json:"name" validate:"trim,required,max=100"`
+	Seats int    `json:"seats" validate:"positive"`
+}
+
+type UpdateDistrictInput struct {
+	Name  string `json:"name" validate:"trim,required,max=100"`
+	Seats int    `json:"seats" validate:"positive"`
+}
+
+func (s *service) CreateDistrict(input *CreateDistrictInput) (*District, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+
+	district := &District{
+		Name:  input.Name,
+		Seats: input.Seats,
+	}
+	id, err := s.repository.Create(district)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	district.ID = int(id)
+	return district, nil
+}
+
+func (s *service) GetAllDistricts() ([]District, error) {
+	return s.repository.FindAll()
+}
+
+func (s *service) GetDistrictByID(id int) (*District, error) {
+	district, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if district == nil {
+		return nil, ErrDistrictNotFound
+	}
+	return district, nil
+}
+
+func (s *service) UpdateDistrict(id int, input *UpdateDistrictInput) (*District, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLocked(id); err != nil {
+		return nil, err
+	}
+
+	districtToUpdate := &District{
+		Name:  input.Name,
+		Seats: input.Seats,
+	}
+	err := s.repository.Update(id, districtToUpdate)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	districtToUpdate.ID = id
+	return districtToUpdate, nil
+}
+
+func (s *service) DeleteDistrict(id int) error {
+	if err := s.checkLocked(id); err != nil {
+		return err
+	}
+	inUse, err := s.repository.IsInUse(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrDistrictInUse
+	}
+	return mapError(s.repository.Delete(id))
+}
+
+// checkLocked refuses changes to a district once an election it takes part in
+// is closed, so that its results and seats stay as they were counted.
+func (s *service) checkLocked(id int) error {
+	locked, err := s.repository.IsLocked(id)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrDistrictLocked
+	}
+	return nil
+}
+
+// mapError turns the errors of a write to the districts table into the
+// errors of this package.
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrDistrictNotFound
+	case database.IsUniqueViolation(err):
+		return ErrNameTaken
+	}
+	return err
+}
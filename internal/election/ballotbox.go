@@ -0,0 +1,177 @@
+package election
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"legiskuy-backend/internal/candidate"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/ledger"
+	mathrand "math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultBallotDelay is how long a ballot waits in the ballot box for others
+// to be stored with when NewService is given no delay.
+const DefaultBallotDelay = time.Second
+
+// ballotBox stores ballots apart from the votes that cast them.
+//
+// A vote stores the voter's participation in a transaction of its own and
+// then drops its ballot into the box, which stores all ballots collected over
+// its delay in one transaction, in random order. On PostgreSQL every row
+// carries the ID of the transaction that wrote it (xmin), so a ballot written
+// in the transaction of its participation could be joined back to the voter;
+// written in a batch, it can only be told to be one of the batch.
+//
+// Ballots that cannot be stored, because the election closed or the
+// transaction failed, are reported back to their votes, which withdraw their
+// participation. A process that dies with ballots in its box leaves
+// participations without ballots, which results verification and recounts
+// report.
+type ballotBox struct {
+	electionRepo  Repository
+	candidateRepo candidate.Repository
+	delay         time.Duration
+
+	// flushing is held for a whole flush, so that a flush started to close
+	// an election returns only once the ballots of a running one are stored.
+	flushing sync.Mutex
+
+	mu      sync.Mutex
+	pending []pendingBallot
+	timer   *time.Timer
+}
+
+type pendingBallot struct {
+	electionID  int
+	candidateID int
+	stored      chan error
+}
+
+func newBallotBox(electionRepo Repository, candidateRepo candidate.Repository, delay time.Duration) *ballotBox {
+	if delay <= 0 {
+		delay = DefaultBallotDelay
+	}
+	return &ballotBox{
+		electionRepo:  electionRepo,
+		candidateRepo: candidateRepo,
+		delay:         delay,
+	}
+}
+
+// cast drops a ballot into the box and waits until it is stored. It returns
+// ErrElectionNotActive if the election was closed before that.
+func (b *ballotBox) cast(electionID, candidateID int) error {
+	stored := make(chan error, 1)
+	b.mu.Lock()
+	b.pending = append(b.pending, pendingBallot{electionID: electionID, candidateID: candidateID, stored: stored})
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.delay, b.flush)
+	}
+	b.mu.Unlock()
+	return <-stored
+}
+
+// flush stores every ballot in the box, one transaction per election.
+func (b *ballotBox) flush() {
+	b.flushing.Lock()
+	defer b.flushing.Unlock()
+
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	byElection := make(map[int][]pendingBallot)
+	for _, p := range pending {
+		byElection[p.electionID] = append(byElection[p.electionID], p)
+	}
+	for electionID, ballots := range byElection {
+		err := b.store(electionID, ballots)
+		for _, p := range ballots {
+			p.stored <- err
+		}
+	}
+}
+
+func (b *ballotBox) store(electionID int, ballots []pendingBallot) error {
+	tx, err := b.electionRepo.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Closing takes the same lock, so a ballot is either stored while the
+	// election is open or not at all.
+	status, err := b.electionRepo.FindStatus(tx, electionID)
+	if err == sql.ErrNoRows || (err == nil && status != StatusOpen) {
+		return ErrElectionNotActive
+	}
+	if err != nil {
+		return err
+	}
+
+	castDate := time.Now().UTC().Format("2006-01-02")
+	stored := make([]storedBallot, 0, len(ballots))
+	hashes := make([]string, 0, len(ballots))
+	votes := make(map[int]int)
+	for _, p := range ballots {
+		id := database.NewBallotID()
+		stored = append(stored, storedBallot{ID: id, CandidateID: p.candidateID, CastDate: castDate})
+		hashes = append(hashes, ledger.HashBallot(electionID, id, p.candidateID, castDate))
+		votes[p.candidateID]++
+	}
+	if err := shuffle(len(stored), func(i, j int) {
+		stored[i], stored[j] = stored[j], stored[i]
+	}); err != nil {
+		return err
+	}
+	if err := b.electionRepo.CreateBallots(tx, electionID, stored); err != nil {
+		return err
+	}
+
+	// The digest only ever grows with the ballots stored here; sealLedger
+	// checks the ballots against it.
+	digest, count, err := b.electionRepo.FindBallotsDigest(tx, electionID)
+	if err != nil {
+		return err
+	}
+	if digest != nil {
+		next, err := ledger.AddToDigest(*digest, hashes...)
+		if err != nil {
+			return err
+		}
+		if err := b.electionRepo.SetBallotsDigest(tx, electionID, next, count+len(stored)); err != nil {
+			return err
+		}
+	}
+
+	counted := make([]int, 0, len(votes))
+	for candidateID := range votes {
+		counted = append(counted, candidateID)
+	}
+	sort.Ints(counted)
+	for _, candidateID := range counted {
+		if err := b.candidateRepo.AddVotes(tx, candidateID, votes[candidateID]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// shuffle puts n elements in random order with a generator seeded from
+// crypto/rand, so that the order cannot be predicted from earlier ones.
+func shuffle(n int, swap func(i, j int)) error {
+	var seed [32]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return err
+	}
+	mathrand.New(mathrand.NewChaCha8(seed)).Shuffle(n, swap)
+	return nil
+}
@@ -0,0 +1,37 @@
+package election
+
+import "legiskuy-backend/pkg/apperror"
+
+var (
+	ErrInvalidID             = apperror.InvalidID("election")
+	ErrElectionNotFound      = apperror.NotFound("election_not_found", "election not found")
+	ErrScheduleIncomplete    = apperror.Field("start_time", "required_together", "start_time and end_time must be set together")
+	ErrEndBeforeStart        = apperror.Field("end_time", "must_be_after_start_time", "end_time must be after start_time")
+	ErrElectionCertified     = apperror.Conflict("election_certified", "certified election cannot be changed")
+	ErrElectionLocked        = apperror.Conflict("election_locked", "schedule, ballot and thresholds cannot be changed once the election is open")
+	ErrScheduleRequired      = apperror.Conflict("schedule_required", "scheduled election must keep its schedule")
+	ErrElectionNotDraft      = apperror.Conflict("election_not_draft", "only draft elections can be deleted")
+	ErrElectionHasCandidates = apperror.Conflict("election_has_candidates", "election still has candidates")
+	ErrCandidatesLocked      = apperror.Conflict("candidates_locked", "candidates cannot be changed once the election is open")
+	ErrDistrictNotFound      = apperror.NotFound("district_not_found", "District not found")
+	ErrVoterNotFound         = apperror.NotFound("voter_not_found", "Voter not found")
+	ErrVoterWithoutDistrict  = apperror.BadRequest("voter_without_district", "voter is not assigned to a district")
+	ErrUnknownStatus         = apperror.Field("status", "invalid_choice", "unknown election status")
+	ErrInvalidTransition     = apperror.Conflict("invalid_transition", "invalid election status transition from {from} to {to}")
+	ErrStatusChanged         = apperror.Conflict("status_changed", "election status has changed, reload and try again")
+	ErrScheduleNotSet        = apperror.Conflict("schedule_not_set", "election schedule must be set before it can be scheduled")
+	ErrNoCandidates          = apperror.Conflict("no_candidates", "election has no candidates")
+	ErrElectionNotStarted    = apperror.Conflict("election_not_started", "election cannot be opened before its start time")
+	ErrElectionEnded         = apperror.Conflict("election_ended", "election cannot be opened after its end time")
+	ErrCertifyForbidden      = apperror.Forbidden("certify_forbidden", "only users with the elections:certify permission can certify an election")
+	ErrCandidateNotFound     = apperror.NotFound("candidate_not_found", "Candidate not found")
+	ErrCandidateNotOnBallot  = apperror.Forbidden("candidate_not_on_ballot", "candidate is not on the voter's district ballot")
+	ErrElectionNotActive     = apperror.Forbidden("election_not_active", "election is not currently active")
+	ErrProxyVoteForbidden    = apperror.Forbidden("proxy_vote_forbidden", "only users with the votes:assist permission can cast a vote on behalf of another voter")
+	ErrReceiptNotFound       = apperror.NotFound("receipt_not_found", "receipt not found")
+	ErrLedgerNotSealed       = apperror.Conflict("ledger_not_sealed", "ledger root is published when the election closes")
+	ErrLedgerEntryNotFound   = apperror.NotFound("ledger_entry_not_found", "ledger entry not found")
+	ErrRepairNotClosed       = apperror.Conflict("repair_not_closed", "vote counters can only be repaired once the election is closed")
+	ErrBallotsTampered       = apperror.Internal("ballots_tampered", "stored ballots do not match the digest kept while the election was open")
+	ErrLedgerIncomplete      = apperror.Internal("ledger_incomplete", "ledger has fewer entries than its published root covers")
+)
@@ -1,6 +1,10 @@
 package election
 
 import (
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/i18n"
+	"legiskuy-backend/pkg/middleware"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,144 +21,569 @@ func NewHandler(service Service) *Handler {
 }
 
 // @Summary Cast a vote
-// @Description Cast a vote for a candidate in the election
+// @Description Cast a vote for a candidate as the voter linked to the logged-in account. Users with the votes:assist permission may set voter_id to record an assisted vote for another voter.
 // @Tags election
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param vote body CastVoteInput true "Vote Data"
-// @Success 200 {object} map[string]string "Vote cast successfully"
-// @Failure 400 {object} map[string]string "Bad request - cannot parse JSON or missing required fields"
-// @Failure 403 {object} map[string]string "Forbidden - election is not currently active"
-// @Failure 404 {object} map[string]string "Not found - voter or candidate not found"
-// @Failure 409 {object} map[string]string "Conflict - voter has already voted"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /vote [post]
+// @Success 200 {object} map[string]interface{} "Vote cast successfully, with the receipt code to look the ballot up later"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON or missing required fields"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - election is not currently active, candidate is outside the voter's district or voter_id used without the votes:assist permission"
+// @Failure 404 {object} apperror.Response "Not found - voter, candidate or election not found"
+// @Failure 409 {object} apperror.Response "Conflict - voter has already voted in this election"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /votes [post]
 func (h *Handler) CastVote(c *fiber.Ctx) error {
 	input := new(CastVoteInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
-	}
-
-	err := h.service.CastVote(input)
-	if err != nil {
-		switch err.Error() {
-		case "voter not found", "candidate not found":
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		case "voter has already voted":
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		case "voter_id and candidate_id are required":
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		case "election is not currently active":
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		default:
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to cast vote",
-			})
-		}
+		return apperror.ErrInvalidJSON
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	receipt, err := h.service.CastVote(userID, middleware.HasPermission(c, rbac.AssistVoters), input)
+	if err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Vote cast successfully",
+		"message":     i18n.Message(c, "vote_cast", "Vote cast successfully"),
+		"election_id": receipt.ElectionID,
+		"receipt":     receipt.Code,
 	})
 }
 
-// @Summary Set election time
-// @Description Set the start and end time for the election
+// @Summary Look up a vote receipt
+// @Description Check that the ballot a receipt was issued for is recorded and, once the election is closed, get the proof that the receipt hash is in the Merkle tree of receipt hashes published then (receipts_root of the ledger summary). Receipts are not linked to ballots, so neither the candidate nor the place of the ballot in the ledger can be found from a receipt.
+// @Tags election
+// @Accept json
+// @Produce json
+// @Param code path string true "Receipt code"
+// @Success 200 {object} ReceiptStatus "Receipt status"
+// @Failure 404 {object} apperror.Response "Not found - receipt not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /receipts/{code} [get]
+func (h *Handler) LookupReceipt(c *fiber.Ctx) error {
+	status, err := h.service.LookupReceipt(c.Params("code"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(status)
+}
+
+// @Summary Create an election
+// @Description Create a new election with its own schedule, thresholds and ballot configuration. party_threshold is the percentage of the election vote a party needs to take part in seat allocation; threshold is the minimum number of votes a candidate needs.
 // @Tags election
 // @Accept json
 // @Produce json
-// @Param time body SetTimeInput true "Election Time Data"
-// @Success 200 {object} map[string]string "Election time set successfully"
-// @Failure 400 {object} map[string]string "Bad request - cannot parse JSON or invalid time format"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /election/time [post]
-func (h *Handler) SetElectionTime(c *fiber.Ctx) error {
-	input := new(SetTimeInput)
+// @Security BearerAuth
+// @Param election body CreateElectionInput true "Election Data"
+// @Success 201 {object} Election "Election created successfully"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON or invalid election data"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections [post]
+func (h *Handler) CreateElection(c *fiber.Ctx) error {
+	input := new(CreateElectionInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
+		return apperror.ErrInvalidJSON
 	}
 
-	err := h.service.SetElectionTime(input)
+	election, err := h.service.CreateElection(input)
 	if err != nil {
-		if err.Error() == "invalid time format, use RFC3339 format (e.g., 2025-06-13T00:00:00Z)" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to set election time",
-		})
+		return err
 	}
+	return c.Status(fiber.StatusCreated).JSON(election)
+}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Election time set successfully",
-	})
+// @Summary Get all elections
+// @Description Get every election held in this deployment
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} Election "List of elections"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections [get]
+func (h *Handler) GetAllElections(c *fiber.Ctx) error {
+	elections, err := h.service.GetAllElections()
+	if err != nil {
+		return err
+	}
+	return c.JSON(elections)
 }
 
-// @Summary Set election threshold
-// @Description Set the minimum vote threshold for candidates to be qualified
+// @Summary Get election by ID
+// @Description Get a specific election by its ID
 // @Tags election
 // @Accept json
 // @Produce json
-// @Param threshold body SetThresholdInput true "Threshold Data"
-// @Success 200 {object} map[string]string "Threshold set successfully"
-// @Failure 400 {object} map[string]string "Bad request - cannot parse JSON or invalid threshold value"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /election/threshold [post]
-func (h *Handler) SetThreshold(c *fiber.Ctx) error {
-	input := new(SetThresholdInput)
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {object} Election "Election details"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id} [get]
+func (h *Handler) GetElectionByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+	election, err := h.service.GetElectionByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(election)
+}
+
+// @Summary Update election
+// @Description Update an election's schedule, thresholds and ballot configuration
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Param election body UpdateElectionInput true "Updated election data"
+// @Success 200 {object} Election "Updated election details"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID, cannot parse JSON or invalid election data"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 409 {object} apperror.Response "Conflict - change not allowed in the election's current status"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id} [put]
+func (h *Handler) UpdateElection(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	input := new(UpdateElectionInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
+		return apperror.ErrInvalidJSON
 	}
 
-	err := h.service.SetThreshold(input)
+	election, err := h.service.UpdateElection(id, input)
 	if err != nil {
-		if err.Error() == "threshold is required" || err.Error() == "threshold must be a non-negative number" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to set threshold",
-		})
+		return err
+	}
+	return c.JSON(election)
+}
+
+// @Summary Delete election
+// @Description Delete a draft election that has no candidates
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {object} map[string]string "Election deleted successfully"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 409 {object} apperror.Response "Conflict - election is not a draft or still has candidates"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id} [delete]
+func (h *Handler) DeleteElection(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	err = h.service.DeleteElection(id)
+	if err != nil {
+		return err
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Threshold set successfully",
+		"message": i18n.Message(c, "election_deleted", "Election deleted successfully"),
 	})
 }
 
+// @Summary Get election ballot
+// @Description Get the ballot of an election for a district, with candidates listed in the configured ballot order. Pemilih get the ballot of their own district; users with the ballots:read_any permission may pick one with district_id or omit it to see every candidate.
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Param district_id query int false "District ID (requires ballots:read_any)"
+// @Success 200 {object} Ballot "Election ballot"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID or voter is not assigned to a district"
+// @Failure 404 {object} apperror.Response "Not found - election, district or voter not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/ballot [get]
+func (h *Handler) GetBallot(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	var ballot *Ballot
+	if middleware.HasPermission(c, rbac.ReadAnyBallot) {
+		ballot, err = h.service.GetBallot(id, c.QueryInt("district_id"))
+	} else {
+		userID, ok := middleware.UserID(c)
+		if !ok {
+			return apperror.ErrUnauthorized
+		}
+		ballot, err = h.service.GetVoterBallot(id, userID)
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(ballot)
+}
+
 // @Summary Get election results
-// @Description Get election results with optional filtering for qualified candidates only
+// @Description Get the results of an election. With qualified=true only candidates whose party passed the party threshold and who reached the candidate threshold are returned.
 // @Tags election
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Param district_id query int false "Only include candidates of this district"
 // @Param qualified query bool false "Filter only qualified candidates (default: false)"
 // @Success 200 {object} map[string]interface{} "Election results"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /election/results [get]
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/results [get]
 func (h *Handler) GetResults(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
 	qualifiedStr := c.Query("qualified", "false")
 	qualifiedOnly, _ := strconv.ParseBool(qualifiedStr)
 
-	results, err := h.service.GetResults(qualifiedOnly)
+	results, err := h.service.GetResults(id, c.QueryInt("district_id"), qualifiedOnly)
+	if err != nil {
+		return err
+	}
+	return c.JSON(results)
+}
+
+// @Summary Get election results per district
+// @Description Get the results of an election grouped by district. Candidates without a district are listed last with a null district.
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {array} DistrictResult "Results per district"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/results/districts [get]
+func (h *Handler) GetDistrictResults(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	results, err := h.service.GetDistrictResults(id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get results",
-		})
+		return err
 	}
 	return c.JSON(results)
 }
+
+// @Summary Get party results
+// @Description Get every party's votes and share (in percent) of the election vote, and whether it passed the election's party_threshold
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {object} PartyResults "Party results"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/results/parties [get]
+func (h *Handler) GetPartyResults(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	results, err := h.service.GetPartyResults(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(results)
+}
+
+// @Summary Verify election results
+// @Description Check that the tallies reconcile with the stored ballots: one ballot per participating voter, and every candidate's vote count equal to the ballots cast for them
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {object} ResultsVerification "Verification report"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/results/verification [get]
+func (h *Handler) VerifyResults(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	verification, err := h.service.VerifyResults(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(verification)
+}
+
+// @Summary Recount election results
+// @Description Recompute every candidate's tally from the stored ballots and report each counter that does not match. With repair set, the counters are overwritten with the ballot counts; this is only allowed while the election is closed, before it is certified. Every run is recorded for audit
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Param recount body RecountInput true "Whether to repair the counters, and an optional note"
+// @Success 201 {object} Recount "Recount report"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID or cannot parse JSON"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 409 {object} apperror.Response "Conflict - repair requested while the election is not closed, or after it is certified"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/recounts [post]
+func (h *Handler) Recount(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	input := new(RecountInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	userID, _ := middleware.UserID(c)
+	recount, err := h.service.Recount(id, userID, input)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(recount)
+}
+
+// @Summary Get election recounts
+// @Description Get the audit history of recounts run on an election, with the discrepancies each one found
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {array} Recount "Recount history"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/recounts [get]
+func (h *Handler) GetRecounts(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	recounts, err := h.service.GetRecounts(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(recounts)
+}
+
+// @Summary Get ledger summary
+// @Description Get the size and head hash of an election's hash-chained ballot ledger, its Merkle root and the Merkle root of its receipt hashes. The ledger is built when the election closes, from its ballots in random order, and is empty with null roots before that.
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {object} LedgerSummary "Ledger summary"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/ledger [get]
+func (h *Handler) GetLedger(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	summary, err := h.service.GetLedger(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(summary)
+}
+
+// @Summary Export ledger
+// @Description Export every ledger entry of a closed election together with its Merkle root and candidate tallies, in the format read by the verify-ledger command. Requires the ledger:read permission.
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {object} LedgerExport "Ledger export"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 403 {object} apperror.Response "Forbidden - requires ledger:read"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 409 {object} apperror.Response "Conflict - ledger not built yet"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/ledger/entries [get]
+func (h *Handler) ExportLedger(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	export, err := h.service.ExportLedger(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(export)
+}
+
+// @Summary Get ledger inclusion proof
+// @Description Get the Merkle inclusion proof of a ledger entry against the root published when the election closed. Requires the ledger:read permission.
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Param seq query int true "Ledger entry sequence number"
+// @Success 200 {object} LedgerProof "Inclusion proof"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 403 {object} apperror.Response "Forbidden - requires ledger:read"
+// @Failure 404 {object} apperror.Response "Not found - election or ledger entry not found"
+// @Failure 409 {object} apperror.Response "Conflict - ledger root not published yet"
+// @Failure 500 {object} apperror.Response "Internal server error, or ledger_incomplete if entries covered by the root are missing"
+// @Router /elections/{id}/ledger/proof [get]
+func (h *Handler) GetLedgerProof(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	proof, err := h.service.GetLedgerProof(id, c.QueryInt("seq"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(proof)
+}
+
+// @Summary Get seat allocation
+// @Description Allocate the seats of every district with the Sainte-Laguë method (divisors 1, 3, 5, ...) on party votes, then give each party's seats to its candidates with the most votes. Quotient ties go to the party with more votes, then to the lower party ballot number; candidate ties go to the lower candidate ID. Parties below the election's party_threshold get no seats, and candidates below its threshold cannot take one. A party gets no more seats than it has eligible candidates in the district, and candidates without a district are left out. Once the election is certified, the allocation made at certification is returned.
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {object} SeatAllocation "Seat allocation per district"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/results/seats [get]
+func (h *Handler) GetSeatAllocation(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	allocation, err := h.service.GetSeatAllocation(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(allocation)
+}
+
+// @Summary Get election state
+// @Description Get the current lifecycle status of an election and the statuses it can move to
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {object} ElectionState "Election state"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/state [get]
+func (h *Handler) GetElectionState(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	state, err := h.service.GetElectionState(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(state)
+}
+
+// @Summary Get election transitions
+// @Description Get the lifecycle transition history of an election
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Success 200 {array} Transition "Transition history"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/transitions [get]
+func (h *Handler) GetTransitions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	history, err := h.service.GetTransitions(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(history)
+}
+
+// @Summary Transition election status
+// @Description Move an election through its lifecycle (draft, scheduled, open, closed, certified)
+// @Tags election
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Election ID"
+// @Param transition body TransitionInput true "Target status"
+// @Success 200 {object} ElectionState "New election state"
+// @Failure 400 {object} apperror.Response "Bad request - invalid election ID, cannot parse JSON or unknown status"
+// @Failure 403 {object} apperror.Response "Forbidden - requires elections:manage, and elections:certify to certify"
+// @Failure 404 {object} apperror.Response "Not found - election not found"
+// @Failure 409 {object} apperror.Response "Conflict - transition not allowed"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /elections/{id}/transitions [post]
+func (h *Handler) TransitionElection(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	input := new(TransitionInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	userID, _ := middleware.UserID(c)
+	state, err := h.service.TransitionElection(id, userID, middleware.HasPermission(c, rbac.CertifyElections), input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(state)
+}
@@ -0,0 +1,86 @@
+package election
+
+import (
+	"time"
+)
+
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusOpen      = "open"
+	StatusClosed    = "closed"
+	StatusCertified = "certified"
+)
+
+// transitions lists, for every status, the statuses it may move to.
+var transitions = map[string][]string{
+	StatusDraft:     {StatusScheduled},
+	StatusScheduled: {StatusDraft, StatusOpen},
+	StatusOpen:      {StatusClosed},
+	StatusClosed:    {StatusCertified},
+	StatusCertified: {},
+}
+
+type Transition struct {
+	ID         int       `json:"id"`
+	ElectionID int       `json:"election_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	UserID     int       `json:"user_id"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ElectionState struct {
+	ElectionID         int      `json:"election_id"`
+	Status             string   `json:"status"`
+	AllowedTransitions []string `json:"allowed_transitions"`
+}
+
+func allowedTransitions(status string) []string {
+	next, ok := transitions[status]
+	if !ok {
+		return []string{}
+	}
+	return next
+}
+
+func canTransition(from, to string) bool {
+	for _, next := range allowedTransitions(from) {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransition validates the guards attached to moving an election to the
+// given status. The caller has already checked that the edge itself exists.
+func checkTransition(election *Election, to string, canCertify, hasCandidates bool, now time.Time) error {
+	switch to {
+	case StatusScheduled:
+		if election.StartTime == nil || election.EndTime == nil {
+			return ErrScheduleNotSet
+		}
+	case StatusOpen:
+		if !hasCandidates {
+			return ErrNoCandidates
+		}
+		if now.Before(election.StartTime.UTC()) {
+			return ErrElectionNotStarted
+		}
+		if now.After(election.EndTime.UTC()) {
+			return ErrElectionEnded
+		}
+	case StatusCertified:
+		if !canCertify {
+			return ErrCertifyForbidden
+		}
+	}
+	return nil
+}
+
+func isValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
@@ -0,0 +1,56 @@
+package election
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"legiskuy-backend/pkg/ledger"
+	"strings"
+)
+
+type VoteReceipt struct {
+	ElectionID int    `json:"election_id"`
+	Code       string `json:"receipt"`
+}
+
+// ReceiptStatus tells the holder of a receipt whether their ballot is
+// recorded and, once the election is closed, proves that the receipt is in
+// the Merkle tree of receipt hashes published then. Receipts are not linked
+// to ballots, so it says nothing about the ballot's place in the ledger or
+// its candidate.
+type ReceiptStatus struct {
+	ElectionID     int                `json:"election_id"`
+	ElectionStatus string             `json:"election_status"`
+	Recorded       bool               `json:"recorded"`
+	Included       bool               `json:"included"`
+	ReceiptHash    string             `json:"receipt_hash"`
+	Steps          []ledger.ProofStep `json:"steps"`
+	ReceiptsRoot   *string            `json:"receipts_root"`
+}
+
+// newReceiptCode returns a random code of six groups of four base32
+// characters, such as "K3QF-7ZLA-...".
+func newReceiptCode() (string, error) {
+	b := make([]byte, 15)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := base32.StdEncoding.EncodeToString(b)
+
+	groups := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		groups = append(groups, raw[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// hashReceipt returns what is stored for a receipt, so that someone reading
+// the database cannot recover receipt codes. Case, spaces and dashes are
+// ignored.
+func hashReceipt(code string) string {
+	normalized := strings.ToUpper(code)
+	normalized = strings.NewReplacer("-", "", " ", "").Replace(normalized)
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,34 @@
+package election
+
+import "time"
+
+type RecountInput struct {
+	Repair bool   `json:"repair"`
+	Note   string `json:"note" validate:"trim,max=500"`
+}
+
+// Recount is the audit record of one recount run: the candidate counters as
+// they were found, every candidate whose counter did not match its ballots,
+// and whether the counters were repaired.
+type Recount struct {
+	ID             int                 `json:"id"`
+	ElectionID     int                 `json:"election_id"`
+	UserID         int                 `json:"user_id"`
+	Repair         bool                `json:"repair"`
+	Note           string              `json:"note"`
+	Participations int                 `json:"participations"`
+	Ballots        int                 `json:"ballots"`
+	TalliedVotes   int                 `json:"tallied_votes"`
+	Discrepancies  []CandidateMismatch `json:"discrepancies"`
+	Repaired       int                 `json:"repaired"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+// candidateCount pairs a candidate's counter with the number of ballots cast
+// for it. Ballots for a candidate that no longer exists have Exists unset.
+type candidateCount struct {
+	CandidateID int
+	Tallied     int
+	Ballots     int
+	Exists      bool
+}
@@ -2,65 +2,562 @@ package election
 
 import (
 	"database/sql"
+	"encoding/json"
+	"legiskuy-backend/internal/voter"
 	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/ledger"
+	"time"
 )
 
+type Election struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	StartTime      *time.Time `json:"start_time"`
+	EndTime        *time.Time `json:"end_time"`
+	Threshold      int        `json:"threshold"`
+	PartyThreshold float64    `json:"party_threshold"`
+	BallotTitle    string     `json:"ballot_title"`
+	BallotOrder    string     `json:"ballot_order"`
+	Status         string     `json:"status"`
+}
+
 type Repository interface {
-	BeginTransaction() (*sql.Tx, error)
-	CreateVote(tx *sql.Tx, voterID, candidateID int) error
+	BeginTransaction() (*database.Tx, error)
+	CreateParticipation(tx *database.Tx, electionID, voterID int, receiptHash string) error
+	DeleteParticipation(tx *database.Tx, electionID, voterID int, receiptHash string) error
+	CreateBallots(tx *database.Tx, electionID int, ballots []storedBallot) error
+	FindBallotsDigest(tx *database.Tx, electionID int) (*string, int, error)
+	SetBallotsDigest(tx *database.Tx, electionID int, digest string, count int) error
+	FindReceipt(receiptHash string) (electionID int, err error)
+	FindReceiptHashes(tx *database.Tx, electionID int) ([]string, error)
+	CountParticipations(tx *database.Tx, electionID int) (int, error)
+	CountTallies(tx *database.Tx, electionID int) ([]candidateCount, error)
+	CreateRecount(tx *database.Tx, recount *Recount) error
+	FindRecounts(electionID int) ([]Recount, error)
+
+	FindLedger(electionID int) ([]ledger.Entry, error)
+	FindBallots(tx *database.Tx, electionID int) ([]storedBallot, error)
+	CreateLedgerEntries(tx *database.Tx, entries []ledger.Entry) error
+	FindLedgerRoot(electionID int) (*string, int, error)
+	SetLedgerRoot(tx *database.Tx, electionID int, root string, size int) error
+	FindReceiptsRoot(electionID int) (*string, error)
+	SetReceiptsRoot(tx *database.Tx, electionID int, root string) error
 
-	GetSetting(key string) (string, error)
-	SetSetting(key, value string) error
+	CreateSeatAllocation(tx *database.Tx, allocation *SeatAllocation, at time.Time) error
+	FindSeatAllocation(electionID int) (*SeatAllocation, error)
+
+	Create(election *Election) (int64, error)
+	FindAll() ([]Election, error)
+	FindByID(id int) (*Election, error)
+	Update(id int, election *Election) error
+	Delete(id int) error
+	HasCandidates(id int) (bool, error)
+
+	FindStatus(tx *database.Tx, id int) (string, error)
+	UpdateStatus(tx *database.Tx, id int, from, to string) error
+	CreateTransition(tx *database.Tx, transition *Transition) error
+	FindTransitions(electionID int) ([]Transition, error)
 }
 
 type repository struct {
-	db *sql.DB
+	db *database.Database
 }
 
-func NewRepository() Repository {
+func NewRepository(db *database.Database) Repository {
 	return &repository{
-		db: database.DB,
+		db: db,
 	}
 }
 
-func (r *repository) BeginTransaction() (*sql.Tx, error) {
+func (r *repository) BeginTransaction() (*database.Tx, error) {
 	return r.db.Begin()
 }
 
-func (r *repository) CreateVote(tx *sql.Tx, voterID, candidateID int) error {
-	query := `INSERT INTO votes (voter_id, candidate_id) VALUES (?, ?)`
-	_, err := tx.Exec(query, voterID, candidateID)
+// CreateParticipation records that the voter took part in the election
+// together with the hash of the receipt they were given. The receipt is
+// stored against the election only, and the ballot is stored later by
+// CreateBallots in a transaction of its own, so that neither can be matched
+// to the voter.
+func (r *repository) CreateParticipation(tx *database.Tx, electionID, voterID int, receiptHash string) error {
+	query := `INSERT INTO participations (election_id, voter_id) VALUES (?, ?)`
+	_, err := tx.Exec(query, electionID, voterID)
+	if database.IsUniqueViolation(err) {
+		return voter.ErrAlreadyVoted
+	}
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO receipts (receipt_hash, election_id) VALUES (?, ?)`
+	_, err = tx.Exec(query, receiptHash, electionID)
+	return err
+}
+
+// DeleteParticipation undoes CreateParticipation for a vote whose ballot
+// could not be stored.
+func (r *repository) DeleteParticipation(tx *database.Tx, electionID, voterID int, receiptHash string) error {
+	query := `DELETE FROM participations WHERE election_id = ? AND voter_id = ?`
+	if _, err := tx.Exec(query, electionID, voterID); err != nil {
+		return err
+	}
+	query = `DELETE FROM receipts WHERE receipt_hash = ? AND election_id = ?`
+	_, err := tx.Exec(query, receiptHash, electionID)
+	return err
+}
+
+// CreateBallots stores the ballots, in the order given, as rows that do not
+// reference any voter. The ballots go into the ledger when the election
+// closes.
+func (r *repository) CreateBallots(tx *database.Tx, electionID int, ballots []storedBallot) error {
+	query := `INSERT INTO ballots (id, election_id, candidate_id, cast_date) VALUES (?, ?, ?, ?)`
+	for _, b := range ballots {
+		if _, err := tx.Exec(query, b.ID, electionID, b.CandidateID, b.CastDate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindBallotsDigest returns the digest of the ballots stored so far and their
+// number, or a nil digest for elections that took ballots before digests were
+// kept.
+func (r *repository) FindBallotsDigest(tx *database.Tx, electionID int) (*string, int, error) {
+	query := `SELECT ballots_digest, ballots_count FROM elections WHERE id = ?`
+	var digest sql.NullString
+	var count int
+	if err := tx.QueryRow(query, electionID).Scan(&digest, &count); err != nil {
+		return nil, 0, err
+	}
+	if !digest.Valid {
+		return nil, count, nil
+	}
+	return &digest.String, count, nil
+}
+
+func (r *repository) SetBallotsDigest(tx *database.Tx, electionID int, digest string, count int) error {
+	query := `UPDATE elections SET ballots_digest = ?, ballots_count = ? WHERE id = ?`
+	_, err := tx.Exec(query, digest, count, electionID)
 	return err
 }
 
-func (r *repository) GetSetting(key string) (string, error) {
-	query := `SELECT value FROM settings WHERE key = ?`
-	row := r.db.QueryRow(query, key)
-	var value string
-	err := row.Scan(&value)
+// FindReceipt returns the election a receipt was issued for, or
+// sql.ErrNoRows.
+func (r *repository) FindReceipt(receiptHash string) (int, error) {
+	query := `SELECT election_id FROM receipts WHERE receipt_hash = ?`
+	var electionID int
+	if err := r.db.QueryRow(query, receiptHash).Scan(&electionID); err != nil {
+		return 0, err
+	}
+	return electionID, nil
+}
+
+func (r *repository) FindReceiptHashes(tx *database.Tx, electionID int) ([]string, error) {
+	query := `SELECT receipt_hash FROM receipts WHERE election_id = ?`
+	rows, err := tx.Query(query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := make([]string, 0)
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+// CountParticipations counts the voters who took part in the election,
+// inside tx.
+func (r *repository) CountParticipations(tx *database.Tx, electionID int) (int, error) {
+	query := `SELECT COUNT(*) FROM participations WHERE election_id = ?`
+	var count int
+	if err := tx.QueryRow(query, electionID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountTallies returns, inside tx, every candidate's counter next to the
+// number of ballots cast for it, followed by ballots whose candidate is gone.
+func (r *repository) CountTallies(tx *database.Tx, electionID int) ([]candidateCount, error) {
+	query := `
+	SELECT c.id, c.votes, (SELECT COUNT(*) FROM ballots b WHERE b.election_id = c.election_id AND b.candidate_id = c.id), TRUE
+	FROM candidates c WHERE c.election_id = ?
+	UNION ALL
+	SELECT b.candidate_id, 0, COUNT(*), FALSE
+	FROM ballots b
+	WHERE b.election_id = ? AND NOT EXISTS (SELECT 1 FROM candidates c WHERE c.id = b.candidate_id AND c.election_id = b.election_id)
+	GROUP BY b.candidate_id
+	ORDER BY 1`
+	rows, err := tx.Query(query, electionID, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]candidateCount, 0)
+	for rows.Next() {
+		var c candidateCount
+		if err := rows.Scan(&c.CandidateID, &c.Tallied, &c.Ballots, &c.Exists); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+func (r *repository) CreateRecount(tx *database.Tx, recount *Recount) error {
+	query := `INSERT INTO recounts (election_id, user_id, repair, note, participations, ballots, tallied_votes, repaired, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := tx.Insert(query, recount.ElectionID, recount.UserID, recount.Repair, recount.Note, recount.Participations, recount.Ballots, recount.TalliedVotes, recount.Repaired, recount.CreatedAt)
+	if err != nil {
+		return err
+	}
+	recount.ID = int(id)
+
+	for _, d := range recount.Discrepancies {
+		query := `INSERT INTO recount_discrepancies (recount_id, candidate_id, tallied, ballots) VALUES (?, ?, ?, ?)`
+		if _, err := tx.Exec(query, recount.ID, d.CandidateID, d.Tallied, d.Ballots); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *repository) FindRecounts(electionID int) ([]Recount, error) {
+	query := `SELECT id, election_id, COALESCE(user_id, 0), repair, note, participations, ballots, tallied_votes, repaired, created_at FROM recounts WHERE election_id = ? ORDER BY id`
+	rows, err := r.db.Query(query, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	recounts := make([]Recount, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		var rc Recount
+		if err := rows.Scan(&rc.ID, &rc.ElectionID, &rc.UserID, &rc.Repair, &rc.Note, &rc.Participations, &rc.Ballots, &rc.TalliedVotes, &rc.Repaired, &rc.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rc.Discrepancies = make([]CandidateMismatch, 0)
+		index[rc.ID] = len(recounts)
+		recounts = append(recounts, rc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `
+	SELECT d.recount_id, d.candidate_id, d.tallied, d.ballots
+	FROM recount_discrepancies d JOIN recounts r ON r.id = d.recount_id
+	WHERE r.election_id = ? ORDER BY d.recount_id, d.candidate_id`
+	rows, err = r.db.Query(query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var recountID int
+		var d CandidateMismatch
+		if err := rows.Scan(&recountID, &d.CandidateID, &d.Tallied, &d.Ballots); err != nil {
+			return nil, err
+		}
+		i := index[recountID]
+		recounts[i].Discrepancies = append(recounts[i].Discrepancies, d)
+	}
+	return recounts, rows.Err()
+}
+
+func (r *repository) Create(election *Election) (int64, error) {
+	query := `INSERT INTO elections (name, description, start_time, end_time, threshold, party_threshold, ballot_title, ballot_order, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.db.Insert(query, election.Name, election.Description, election.StartTime, election.EndTime, election.Threshold, election.PartyThreshold, election.BallotTitle, election.BallotOrder, election.Status)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *repository) FindAll() ([]Election, error) {
+	query := `SELECT id, name, description, start_time, end_time, threshold, party_threshold, ballot_title, ballot_order, status FROM elections ORDER BY id`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	elections := make([]Election, 0)
+	for rows.Next() {
+		e, err := scanElection(rows)
+		if err != nil {
+			return nil, err
+		}
+		elections = append(elections, *e)
+	}
+	return elections, nil
+}
+
+func (r *repository) FindByID(id int) (*Election, error) {
+	query := `SELECT id, name, description, start_time, end_time, threshold, party_threshold, ballot_title, ballot_order, status FROM elections WHERE id = ?`
+	e, err := scanElection(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return "", nil
+			return nil, nil
 		}
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *repository) Update(id int, election *Election) error {
+	query := `UPDATE elections SET name = ?, description = ?, start_time = ?, end_time = ?, threshold = ?, party_threshold = ?, ballot_title = ?, ballot_order = ? WHERE id = ?`
+	result, err := r.db.Exec(query, election.Name, election.Description, election.StartTime, election.EndTime, election.Threshold, election.PartyThreshold, election.BallotTitle, election.BallotOrder, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) Delete(id int) error {
+	query := `DELETE FROM elections WHERE id = ?`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) HasCandidates(id int) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM candidates WHERE election_id = ?)`
+	var exists bool
+	if err := r.db.QueryRow(query, id).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// FindStatus reads the status of the election and locks its row until tx
+// ends, so that votes, recounts and status changes of one election take
+// turns.
+func (r *repository) FindStatus(tx *database.Tx, id int) (string, error) {
+	query := `SELECT status FROM elections WHERE id = ?` + tx.ForUpdate()
+	var status string
+	if err := tx.QueryRow(query, id).Scan(&status); err != nil {
 		return "", err
 	}
-	return value, nil
+	return status, nil
 }
 
-func (r *repository) SetSetting(key, value string) error {
-	queryUpdate := `UPDATE settings SET value = ? WHERE key = ?`
-	res, err := r.db.Exec(queryUpdate, value, key)
+// UpdateStatus only moves the election if it is still in the expected status,
+// and reports sql.ErrNoRows otherwise.
+func (r *repository) UpdateStatus(tx *database.Tx, id int, from, to string) error {
+	query := `UPDATE elections SET status = ? WHERE id = ? AND status = ?`
+	result, err := tx.Exec(query, to, id, from)
 	if err != nil {
 		return err
 	}
-	rowsAffected, err := res.RowsAffected()
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 	if rowsAffected == 0 {
-		queryInsert := `INSERT INTO settings (key, value) VALUES (?, ?)`
-		_, err := r.db.Exec(queryInsert, key, value)
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) CreateTransition(tx *database.Tx, transition *Transition) error {
+	query := `INSERT INTO election_transitions (election_id, from_status, to_status, user_id, note, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	id, err := tx.Insert(query, transition.ElectionID, transition.FromStatus, transition.ToStatus, transition.UserID, transition.Note, transition.CreatedAt)
+	if err != nil {
 		return err
 	}
+	transition.ID = int(id)
 	return nil
 }
+
+func (r *repository) FindTransitions(electionID int) ([]Transition, error) {
+	query := `SELECT id, election_id, from_status, to_status, COALESCE(user_id, 0), note, created_at FROM election_transitions WHERE election_id = ? ORDER BY id`
+	rows, err := r.db.Query(query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]Transition, 0)
+	for rows.Next() {
+		var t Transition
+		if err := rows.Scan(&t.ID, &t.ElectionID, &t.FromStatus, &t.ToStatus, &t.UserID, &t.Note, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, t)
+	}
+	return history, nil
+}
+
+func (r *repository) FindLedger(electionID int) ([]ledger.Entry, error) {
+	query := `SELECT election_id, seq, ballot_id, candidate_id, cast_date, prev_hash, hash FROM ledger_entries WHERE election_id = ? ORDER BY seq`
+	rows, err := r.db.Query(query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]ledger.Entry, 0)
+	for rows.Next() {
+		var e ledger.Entry
+		if err := rows.Scan(&e.ElectionID, &e.Seq, &e.BallotID, &e.CandidateID, &e.CastDate, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// storedBallot is a ballot as it is kept until the ledger is built from it.
+type storedBallot struct {
+	ID          string
+	CandidateID int
+	CastDate    string
+}
+
+// FindBallots returns the ballots of the election, inside tx, in no
+// particular order.
+func (r *repository) FindBallots(tx *database.Tx, electionID int) ([]storedBallot, error) {
+	query := `SELECT id, candidate_id, cast_date FROM ballots WHERE election_id = ?`
+	rows, err := tx.Query(query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ballots := make([]storedBallot, 0)
+	for rows.Next() {
+		var b storedBallot
+		var castDate time.Time
+		if err := rows.Scan(&b.ID, &b.CandidateID, &castDate); err != nil {
+			return nil, err
+		}
+		b.CastDate = castDate.Format("2006-01-02")
+		ballots = append(ballots, b)
+	}
+	return ballots, rows.Err()
+}
+
+func (r *repository) CreateLedgerEntries(tx *database.Tx, entries []ledger.Entry) error {
+	query := `INSERT INTO ledger_entries (election_id, seq, ballot_id, candidate_id, cast_date, prev_hash, hash) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	for _, e := range entries {
+		if _, err := tx.Exec(query, e.ElectionID, e.Seq, e.BallotID, e.CandidateID, e.CastDate, e.PrevHash, e.Hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindLedgerRoot returns the published Merkle root and the number of entries
+// it covers, or a nil root while the election has not been closed.
+func (r *repository) FindLedgerRoot(electionID int) (*string, int, error) {
+	query := `SELECT ledger_root, COALESCE(ledger_size, 0) FROM elections WHERE id = ?`
+	var root sql.NullString
+	var size int
+	if err := r.db.QueryRow(query, electionID).Scan(&root, &size); err != nil {
+		return nil, 0, err
+	}
+	if !root.Valid {
+		return nil, 0, nil
+	}
+	return &root.String, size, nil
+}
+
+func (r *repository) SetLedgerRoot(tx *database.Tx, electionID int, root string, size int) error {
+	query := `UPDATE elections SET ledger_root = ?, ledger_size = ? WHERE id = ?`
+	_, err := tx.Exec(query, root, size, electionID)
+	return err
+}
+
+// FindReceiptsRoot returns the Merkle root of the receipt hashes published
+// when the election closed, or nil before that.
+func (r *repository) FindReceiptsRoot(electionID int) (*string, error) {
+	query := `SELECT receipts_root FROM elections WHERE id = ?`
+	var root sql.NullString
+	if err := r.db.QueryRow(query, electionID).Scan(&root); err != nil {
+		return nil, err
+	}
+	if !root.Valid {
+		return nil, nil
+	}
+	return &root.String, nil
+}
+
+func (r *repository) SetReceiptsRoot(tx *database.Tx, electionID int, root string) error {
+	query := `UPDATE elections SET receipts_root = ? WHERE id = ?`
+	_, err := tx.Exec(query, root, electionID)
+	return err
+}
+
+// CreateSeatAllocation stores the allocation of a certified election.
+func (r *repository) CreateSeatAllocation(tx *database.Tx, allocation *SeatAllocation, at time.Time) error {
+	data, err := json.Marshal(allocation)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO seat_allocations (election_id, allocation, created_at) VALUES (?, ?, ?)`
+	_, err = tx.Exec(query, allocation.ElectionID, string(data), at)
+	return err
+}
+
+// FindSeatAllocation returns the allocation stored when the election was
+// certified, or nil if there is none.
+func (r *repository) FindSeatAllocation(electionID int) (*SeatAllocation, error) {
+	query := `SELECT allocation FROM seat_allocations WHERE election_id = ?`
+	var data string
+	if err := r.db.QueryRow(query, electionID).Scan(&data); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var allocation SeatAllocation
+	if err := json.Unmarshal([]byte(data), &allocation); err != nil {
+		return nil, err
+	}
+	return &allocation, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanElection(row scanner) (*Election, error) {
+	var e Election
+	var startTime, endTime sql.NullTime
+	err := row.Scan(&e.ID, &e.Name, &e.Description, &startTime, &endTime, &e.Threshold, &e.PartyThreshold, &e.BallotTitle, &e.BallotOrder, &e.Status)
+	if err != nil {
+		return nil, err
+	}
+	if startTime.Valid {
+		e.StartTime = &startTime.Time
+	}
+	if endTime.Valid {
+		e.EndTime = &endTime.Time
+	}
+	return &e, nil
+}
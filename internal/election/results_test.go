@@ -0,0 +1,91 @@
+package election_test
+
+import (
+	"fmt"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/testserver"
+	"net/http"
+	"testing"
+)
+
+// TestSeatAllocationCertified checks that the seats of a certified election
+// are those allocated at certification.
+func TestSeatAllocationCertified(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		e := srv.OpenElection(token)
+		srv.Transition(token, e.ID, "closed")
+		srv.Transition(token, e.ID, "certified")
+
+		var district struct {
+			ID int `json:"id"`
+		}
+		srv.Create(token, "/api/v1/districts", map[string]interface{}{"name": "Dapil 2", "seats": 1}, &district)
+
+		var allocation struct {
+			Districts []struct {
+				District struct {
+					ID int `json:"id"`
+				} `json:"district"`
+			} `json:"districts"`
+		}
+		path := fmt.Sprintf("/api/v1/elections/%d/results/seats", e.ID)
+		srv.Decode(srv.Request(http.MethodGet, path, token, nil), &allocation)
+		if len(allocation.Districts) != 1 || allocation.Districts[0].District.ID != e.DistrictID {
+			t.Fatalf("got districts %+v, want only district %d", allocation.Districts, e.DistrictID)
+		}
+	})
+}
+
+// TestRepairOnlyWhenClosed checks that the vote counters can only be
+// repaired between close and certification, while a plain recount is always
+// allowed.
+func TestRepairOnlyWhenClosed(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		e := srv.OpenElection(token)
+
+		path := fmt.Sprintf("/api/v1/elections/%d/recounts", e.ID)
+		repair := map[string]interface{}{"repair": true}
+		srv.Expect(srv.Request(http.MethodPost, path, token, map[string]interface{}{}), http.StatusCreated)
+		srv.Expect(srv.Request(http.MethodPost, path, token, repair), http.StatusConflict)
+
+		srv.Transition(token, e.ID, "closed")
+		srv.Expect(srv.Request(http.MethodPost, path, token, repair), http.StatusCreated)
+
+		srv.Transition(token, e.ID, "certified")
+		resp := srv.Request(http.MethodPost, path, token, repair)
+		var body struct {
+			Code string `json:"code"`
+		}
+		if resp.StatusCode != http.StatusConflict {
+			resp.Body.Close()
+			t.Fatalf("repair after certification: got %d, want %d", resp.StatusCode, http.StatusConflict)
+		}
+		srv.Decode(resp, &body)
+		if body.Code != "election_certified" {
+			t.Fatalf("repair after certification: got code %q, want election_certified", body.Code)
+		}
+	})
+}
+
+// TestVerifyResults checks that every participation has its ballot and
+// counter once the votes are answered.
+func TestVerifyResults(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		e := srv.OpenElection(token)
+		castVotes(srv, token, e, 3)
+
+		var verification struct {
+			Participations int  `json:"participations"`
+			Ballots        int  `json:"ballots"`
+			TalliedVotes   int  `json:"tallied_votes"`
+			Consistent     bool `json:"consistent"`
+		}
+		srv.Decode(srv.Request(http.MethodGet, fmt.Sprintf("/api/v1/elections/%d/results/verification", e.ID), token, nil), &verification)
+		if !verification.Consistent || verification.Participations != 3 || verification.Ballots != 3 || verification.TalliedVotes != 3 {
+			t.Fatalf("got %+v, want three consistent votes", verification)
+		}
+	})
+}
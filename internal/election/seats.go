@@ -0,0 +1,201 @@
+package election
+
+import (
+	"legiskuy-backend/internal/candidate"
+	"legiskuy-backend/internal/district"
+	"legiskuy-backend/internal/party"
+	"sort"
+)
+
+type SeatRound struct {
+	Seat     int     `json:"seat"`
+	PartyID  int     `json:"party_id"`
+	Quotient float64 `json:"quotient"`
+}
+
+type PartySeats struct {
+	Party   *party.Party          `json:"party"`
+	Votes   int                   `json:"votes"`
+	Passed  bool                  `json:"passed"`
+	Seats   int                   `json:"seats"`
+	Elected []candidate.Candidate `json:"elected"`
+}
+
+type DistrictSeats struct {
+	District    *district.District `json:"district"`
+	TotalVotes  int                `json:"total_votes"`
+	SeatsFilled int                `json:"seats_filled"`
+	Parties     []PartySeats       `json:"parties"`
+	Rounds      []SeatRound        `json:"rounds"`
+}
+
+type SeatAllocation struct {
+	ElectionID     int             `json:"election_id"`
+	PartyThreshold float64         `json:"party_threshold"`
+	Parties        []PartyResult   `json:"parties"`
+	Districts      []DistrictSeats `json:"districts"`
+}
+
+type PartyResult struct {
+	Party  *party.Party `json:"party"`
+	Votes  int          `json:"votes"`
+	Share  float64      `json:"share"`
+	Passed bool         `json:"passed"`
+}
+
+type PartyResults struct {
+	ElectionID     int           `json:"election_id"`
+	PartyThreshold float64       `json:"party_threshold"`
+	TotalVotes     int           `json:"total_votes"`
+	Parties        []PartyResult `json:"parties"`
+}
+
+// tallyParties sums the votes of every party over the given candidates and
+// checks its share, in percent, against the party threshold. Parties are
+// returned in ballot number order.
+func tallyParties(candidates []candidate.Candidate, threshold float64) []PartyResult {
+	results := make([]PartyResult, 0)
+	index := make(map[int]int)
+	totalVotes := 0
+	for _, c := range candidates {
+		i, ok := index[c.PartyID]
+		if !ok {
+			i = len(results)
+			index[c.PartyID] = i
+			results = append(results, PartyResult{Party: c.Party})
+		}
+		results[i].Votes += c.Votes
+		totalVotes += c.Votes
+	}
+
+	for i := range results {
+		if totalVotes > 0 {
+			results[i].Share = float64(results[i].Votes) * 100 / float64(totalVotes)
+			results[i].Passed = float64(results[i].Votes)*100 >= threshold*float64(totalVotes)
+		} else {
+			results[i].Passed = threshold == 0
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Party.BallotNumber < results[j].Party.BallotNumber
+	})
+	return results
+}
+
+func passedParties(results []PartyResult) map[int]bool {
+	passed := make(map[int]bool, len(results))
+	for _, r := range results {
+		passed[r.Party.ID] = r.Passed
+	}
+	return passed
+}
+
+type partyTally struct {
+	party      *party.Party
+	passed     bool
+	votes      int
+	seats      int
+	candidates []candidate.Candidate
+}
+
+// allocateSeats distributes the seats of one district with the Sainte-Laguë
+// divisor method: every seat goes to the party with the highest quotient
+// votes / (2s + 1), where s is the number of seats it already holds.
+//
+// Ties on the quotient go to the party with more votes, then to the party
+// with the lower ballot number. A party never receives more seats than it has
+// candidates in the district, and parties without votes receive none, so a
+// district can end up with fewer seats filled than it offers.
+//
+// Within a party, seats go to its candidates by votes, ties going to the
+// candidate with the lower ID. Parties that did not pass the party threshold
+// are left out, and candidates below minVotes still add to their party's
+// votes but cannot take a seat.
+func allocateSeats(d *district.District, candidates []candidate.Candidate, passed map[int]bool, minVotes int) DistrictSeats {
+	tallies := make([]*partyTally, 0)
+	byParty := make(map[int]*partyTally)
+	totalVotes := 0
+	for _, c := range candidates {
+		t, ok := byParty[c.PartyID]
+		if !ok {
+			t = &partyTally{party: c.Party, passed: passed[c.PartyID], candidates: make([]candidate.Candidate, 0)}
+			byParty[c.PartyID] = t
+			tallies = append(tallies, t)
+		}
+		t.votes += c.Votes
+		if c.Votes >= minVotes {
+			t.candidates = append(t.candidates, c)
+		}
+		totalVotes += c.Votes
+	}
+
+	rounds := make([]SeatRound, 0, d.Seats)
+	for seat := 1; seat <= d.Seats; seat++ {
+		var winner *partyTally
+		for _, t := range tallies {
+			if !t.passed || t.votes == 0 || t.seats >= len(t.candidates) {
+				continue
+			}
+			if winner == nil || beats(t, winner) {
+				winner = t
+			}
+		}
+		if winner == nil {
+			break
+		}
+		rounds = append(rounds, SeatRound{
+			Seat:     seat,
+			PartyID:  winner.party.ID,
+			Quotient: float64(winner.votes) / float64(2*winner.seats+1),
+		})
+		winner.seats++
+	}
+
+	parties := make([]PartySeats, 0, len(tallies))
+	for _, t := range tallies {
+		sort.Slice(t.candidates, func(i, j int) bool {
+			if t.candidates[i].Votes != t.candidates[j].Votes {
+				return t.candidates[i].Votes > t.candidates[j].Votes
+			}
+			return t.candidates[i].ID < t.candidates[j].ID
+		})
+		parties = append(parties, PartySeats{
+			Party:   t.party,
+			Votes:   t.votes,
+			Passed:  t.passed,
+			Seats:   t.seats,
+			Elected: t.candidates[:t.seats],
+		})
+	}
+	sort.Slice(parties, func(i, j int) bool {
+		if parties[i].Seats != parties[j].Seats {
+			return parties[i].Seats > parties[j].Seats
+		}
+		if parties[i].Votes != parties[j].Votes {
+			return parties[i].Votes > parties[j].Votes
+		}
+		return parties[i].Party.BallotNumber < parties[j].Party.BallotNumber
+	})
+
+	return DistrictSeats{
+		District:    d,
+		TotalVotes:  totalVotes,
+		SeatsFilled: len(rounds),
+		Parties:     parties,
+		Rounds:      rounds,
+	}
+}
+
+// beats reports whether party a wins the next seat over party b. Quotients
+// are compared by cross-multiplication so that no precision is lost.
+func beats(a, b *partyTally) bool {
+	left := a.votes * (2*b.seats + 1)
+	right := b.votes * (2*a.seats + 1)
+	if left != right {
+		return left > right
+	}
+	if a.votes != b.votes {
+		return a.votes > b.votes
+	}
+	return a.party.BallotNumber < b.party.BallotNumber
+}
@@ -0,0 +1,227 @@
+package election
+
+import (
+	"legiskuy-backend/internal/candidate"
+	"legiskuy-backend/internal/district"
+	"legiskuy-backend/internal/party"
+	"reflect"
+	"testing"
+)
+
+// partySpec describes a party of a test district: the votes of each of its
+// candidates, in candidate ID order.
+type partySpec struct {
+	id     int
+	ballot int
+	failed bool
+	votes  []int
+}
+
+// candidatesOf builds the candidates of the given parties with IDs counting
+// up from 1, together with the parties that passed the threshold.
+func candidatesOf(specs []partySpec) ([]candidate.Candidate, map[int]bool) {
+	candidates := make([]candidate.Candidate, 0)
+	passed := make(map[int]bool)
+	for _, s := range specs {
+		ballot := s.ballot
+		if ballot == 0 {
+			ballot = s.id
+		}
+		p := &party.Party{ID: s.id, BallotNumber: ballot}
+		passed[s.id] = !s.failed
+		for _, votes := range s.votes {
+			candidates = append(candidates, candidate.Candidate{
+				ID:      len(candidates) + 1,
+				PartyID: s.id,
+				Party:   p,
+				Votes:   votes,
+			})
+		}
+	}
+	return candidates, passed
+}
+
+func TestAllocateSeats(t *testing.T) {
+	tests := []struct {
+		name     string
+		seats    int
+		minVotes int
+		parties  []partySpec
+		// rounds lists the party winning each seat, in order.
+		rounds []int
+		// elected lists the candidates elected for each party.
+		elected map[int][]int
+	}{
+		{
+			// Quotients 53000, 17667, 10600, 7571 against 24000, 8000 and
+			// 23000, 7667: the last seat goes to 7667 over 7571.
+			name:  "divisors 1, 3, 5, 7",
+			seats: 7,
+			parties: []partySpec{
+				{id: 1, votes: []int{20000, 15000, 10000, 8000}},
+				{id: 2, votes: []int{14000, 10000, 0}},
+				{id: 3, votes: []int{13000, 10000, 0}},
+			},
+			rounds:  []int{1, 2, 3, 1, 1, 2, 3},
+			elected: map[int][]int{1: {1, 2, 3}, 2: {5, 6}, 3: {8, 9}},
+		},
+		{
+			// Unlike D'Hondt, which would give both seats to the larger
+			// party, 30/3 ties with 10/1 and the tie goes to more votes.
+			name:  "tied quotient goes to more votes",
+			seats: 2,
+			parties: []partySpec{
+				{id: 1, votes: []int{20, 10}},
+				{id: 2, votes: []int{10}},
+			},
+			rounds:  []int{1, 1},
+			elected: map[int][]int{1: {1, 2}, 2: {}},
+		},
+		{
+			name:  "tied votes go to the lower ballot number",
+			seats: 1,
+			parties: []partySpec{
+				{id: 1, ballot: 2, votes: []int{10}},
+				{id: 2, ballot: 1, votes: []int{10}},
+			},
+			rounds:  []int{2},
+			elected: map[int][]int{1: {}, 2: {2}},
+		},
+		{
+			name:  "tied candidates go to the lower ID",
+			seats: 1,
+			parties: []partySpec{
+				{id: 1, votes: []int{5, 7, 7}},
+			},
+			rounds:  []int{1},
+			elected: map[int][]int{1: {2}},
+		},
+		{
+			name:  "parties without votes get no seats",
+			seats: 3,
+			parties: []partySpec{
+				{id: 1, votes: []int{5, 5}},
+				{id: 2, votes: []int{0, 0}},
+			},
+			rounds:  []int{1, 1},
+			elected: map[int][]int{1: {1, 2}, 2: {}},
+		},
+		{
+			name:  "no votes at all",
+			seats: 2,
+			parties: []partySpec{
+				{id: 1, votes: []int{0}},
+			},
+			rounds:  []int{},
+			elected: map[int][]int{1: {}},
+		},
+		{
+			name:  "more seats than candidates",
+			seats: 5,
+			parties: []partySpec{
+				{id: 1, votes: []int{100}},
+				{id: 2, votes: []int{1}},
+			},
+			rounds:  []int{1, 2},
+			elected: map[int][]int{1: {1}, 2: {2}},
+		},
+		{
+			name:  "parties below the threshold",
+			seats: 2,
+			parties: []partySpec{
+				{id: 1, failed: true, votes: []int{100, 100}},
+				{id: 2, votes: []int{10, 1}},
+			},
+			rounds:  []int{2, 2},
+			elected: map[int][]int{1: {}, 2: {3, 4}},
+		},
+		{
+			// The candidate below minVotes still counts for the party but
+			// cannot take its second seat.
+			name:     "candidates below the minimum votes",
+			seats:    3,
+			minVotes: 5,
+			parties: []partySpec{
+				{id: 1, votes: []int{50, 1}},
+				{id: 2, votes: []int{40}},
+			},
+			rounds:  []int{1, 2},
+			elected: map[int][]int{1: {1}, 2: {3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, passed := candidatesOf(tt.parties)
+			result := allocateSeats(&district.District{ID: 1, Seats: tt.seats}, candidates, passed, tt.minVotes)
+
+			rounds := make([]int, 0, len(result.Rounds))
+			for i, r := range result.Rounds {
+				if r.Seat != i+1 {
+					t.Errorf("round %d is for seat %d", i, r.Seat)
+				}
+				rounds = append(rounds, r.PartyID)
+			}
+			if !reflect.DeepEqual(rounds, tt.rounds) {
+				t.Errorf("got rounds %v, want %v", rounds, tt.rounds)
+			}
+			if result.SeatsFilled != len(tt.rounds) {
+				t.Errorf("got %d seats filled, want %d", result.SeatsFilled, len(tt.rounds))
+			}
+
+			elected := make(map[int][]int)
+			for _, p := range result.Parties {
+				ids := make([]int, 0, len(p.Elected))
+				for _, c := range p.Elected {
+					ids = append(ids, c.ID)
+				}
+				if p.Seats != len(ids) {
+					t.Errorf("party %d has %d seats and %d elected", p.Party.ID, p.Seats, len(ids))
+				}
+				elected[p.Party.ID] = ids
+			}
+			if !reflect.DeepEqual(elected, tt.elected) {
+				t.Errorf("got elected %v, want %v", elected, tt.elected)
+			}
+		})
+	}
+}
+
+func TestAllocateSeatsQuotients(t *testing.T) {
+	candidates, passed := candidatesOf([]partySpec{{id: 1, votes: []int{30, 0, 0}}})
+	result := allocateSeats(&district.District{Seats: 3}, candidates, passed, 0)
+	want := []float64{30, 10, 6}
+	for i, r := range result.Rounds {
+		if r.Quotient != want[i] {
+			t.Errorf("seat %d: got quotient %v, want %v", r.Seat, r.Quotient, want[i])
+		}
+	}
+	if result.TotalVotes != 30 {
+		t.Errorf("got %d total votes, want 30", result.TotalVotes)
+	}
+}
+
+func TestBeats(t *testing.T) {
+	tally := func(votes, seats, ballot int) *partyTally {
+		return &partyTally{party: &party.Party{BallotNumber: ballot}, votes: votes, seats: seats}
+	}
+	tests := []struct {
+		name string
+		a, b *partyTally
+		want bool
+	}{
+		{"higher quotient", tally(10, 0, 2), tally(29, 1, 1), true},
+		{"lower quotient", tally(10, 0, 2), tally(31, 1, 1), false},
+		{"tied quotient, more votes", tally(30, 1, 2), tally(10, 0, 1), true},
+		{"tied quotient, fewer votes", tally(10, 0, 1), tally(30, 1, 2), false},
+		{"tied votes, lower ballot number", tally(10, 0, 1), tally(10, 0, 2), true},
+		{"tied votes, higher ballot number", tally(10, 0, 2), tally(10, 0, 1), false},
+		// 1000001/3 is 333333.67, just below 333334/1.
+		{"close quotients", tally(1000001, 1, 1), tally(333334, 0, 2), false},
+		{"close quotients reversed", tally(333334, 0, 2), tally(1000001, 1, 1), true},
+	}
+	for _, tt := range tests {
+		if got := beats(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
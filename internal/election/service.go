@@ -1,141 +1,1125 @@
 package election
 
 import (
+	"database/sql"
 	"errors"
 	"legiskuy-backend/internal/candidate"
+	"legiskuy-backend/internal/district"
+	"legiskuy-backend/internal/party"
 	"legiskuy-backend/internal/voter"
-	"strconv"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/ledger"
+	"legiskuy-backend/pkg/validate"
+	"sort"
 	"time"
 )
 
 type Service interface {
-	CastVote(input *CastVoteInput) error
-	SetElectionTime(input *SetTimeInput) error
-	GetResults(qualifiedOnly bool) ([]candidate.Candidate, error)
-	SetThreshold(input *SetThresholdInput) error
+	CreateElection(input *CreateElectionInput) (*Election, error)
+	GetAllElections() ([]Election, error)
+	GetElectionByID(id int) (*Election, error)
+	UpdateElection(id int, input *UpdateElectionInput) (*Election, error)
+	DeleteElection(id int) error
+	GetBallot(electionID, districtID int) (*Ballot, error)
+	GetVoterBallot(electionID, userID int) (*Ballot, error)
+	CheckCandidateChanges(electionID int) error
+
+	GetElectionState(electionID int) (*ElectionState, error)
+	GetTransitions(electionID int) ([]Transition, error)
+	TransitionElection(electionID, userID int, canCertify bool, input *TransitionInput) (*ElectionState, error)
+
+	CastVote(userID int, canAssist bool, input *CastVoteInput) (*VoteReceipt, error)
+	LookupReceipt(code string) (*ReceiptStatus, error)
+	GetResults(electionID, districtID int, qualifiedOnly bool) ([]candidate.Candidate, error)
+	GetDistrictResults(electionID int) ([]DistrictResult, error)
+	GetPartyResults(electionID int) (*PartyResults, error)
+	GetSeatAllocation(electionID int) (*SeatAllocation, error)
+	VerifyResults(electionID int) (*ResultsVerification, error)
+	Recount(electionID, userID int, input *RecountInput) (*Recount, error)
+	GetRecounts(electionID int) ([]Recount, error)
+
+	GetLedger(electionID int) (*LedgerSummary, error)
+	ExportLedger(electionID int) (*LedgerExport, error)
+	GetLedgerProof(electionID, seq int) (*LedgerProof, error)
 }
 
 type service struct {
 	electionRepo  Repository
 	voterRepo     voter.Repository
 	candidateRepo candidate.Repository
+	districtRepo  district.Repository
+	box           *ballotBox
 }
 
-func NewService(electionRepo Repository, voterRepo voter.Repository, candidateRepo candidate.Repository) Service {
+// NewService returns the election service. Ballots wait ballotDelay, or
+// DefaultBallotDelay when it is zero, to be stored together with others; see
+// ballotBox.
+func NewService(electionRepo Repository, voterRepo voter.Repository, candidateRepo candidate.Repository, districtRepo district.Repository, ballotDelay time.Duration) Service {
 	return &service{
 		electionRepo:  electionRepo,
 		voterRepo:     voterRepo,
 		candidateRepo: candidateRepo,
+		districtRepo:  districtRepo,
+		box:           newBallotBox(electionRepo, candidateRepo, ballotDelay),
 	}
 }
 
 type CastVoteInput struct {
-	VoterID     int `json:"voter_id"`
+	VoterID     int `json:"voter_id,omitempty"`
+	CandidateID int `json:"candidate_id" validate:"required"`
+}
+
+type CreateElectionInput struct {
+	Name           string   `json:"name" validate:"trim,required,max=200"`
+	Description    string   `json:"description" validate:"trim,max=2000"`
+	StartTime      string   `json:"start_time" validate:"trim,rfc3339"`
+	EndTime        string   `json:"end_time" validate:"trim,rfc3339"`
+	Threshold      *int     `json:"threshold" validate:"nonnegative"`
+	PartyThreshold *float64 `json:"party_threshold" validate:"between=0 100"`
+	BallotTitle    string   `json:"ballot_title" validate:"trim,max=200"`
+	BallotOrder    string   `json:"ballot_order" validate:"oneof=id name party"`
+}
+
+type UpdateElectionInput struct {
+	Name           string   `json:"name" validate:"trim,required,max=200"`
+	Description    string   `json:"description" validate:"trim,max=2000"`
+	StartTime      string   `json:"start_time" validate:"trim,rfc3339"`
+	EndTime        string   `json:"end_time" validate:"trim,rfc3339"`
+	Threshold      *int     `json:"threshold" validate:"nonnegative"`
+	PartyThreshold *float64 `json:"party_threshold" validate:"between=0 100"`
+	BallotTitle    string   `json:"ballot_title" validate:"trim,max=200"`
+	BallotOrder    string   `json:"ballot_order" validate:"oneof=id name party"`
+}
+
+type TransitionInput struct {
+	Status string `json:"status" validate:"trim,required"`
+	Note   string `json:"note" validate:"trim,max=500"`
+}
+
+type BallotEntry struct {
+	CandidateID int          `json:"candidate_id"`
+	Name        string       `json:"name"`
+	Party       *party.Party `json:"party"`
+}
+
+type Ballot struct {
+	ElectionID int           `json:"election_id"`
+	DistrictID *int          `json:"district_id"`
+	Title      string        `json:"title"`
+	Entries    []BallotEntry `json:"entries"`
+}
+
+type CandidateMismatch struct {
 	CandidateID int `json:"candidate_id"`
+	Tallied     int `json:"tallied"`
+	Ballots     int `json:"ballots"`
 }
 
-type SetTimeInput struct {
-	StartTime string `json:"start_time"`
-	EndTime   string `json:"end_time"`
+type ResultsVerification struct {
+	ElectionID     int                 `json:"election_id"`
+	Participations int                 `json:"participations"`
+	Ballots        int                 `json:"ballots"`
+	TalliedVotes   int                 `json:"tallied_votes"`
+	Mismatches     []CandidateMismatch `json:"mismatches"`
+	Consistent     bool                `json:"consistent"`
 }
 
-type SetThresholdInput struct {
-	Threshold *int `json:"threshold"`
+type LedgerSummary struct {
+	ElectionID int     `json:"election_id"`
+	Size       int     `json:"size"`
+	HeadHash   string  `json:"head_hash"`
+	MerkleRoot *string `json:"merkle_root"`
+	RootSize   int     `json:"root_size"`
+	// ReceiptsRoot is the Merkle root of the receipt hashes, which receipt
+	// lookups prove inclusion against.
+	ReceiptsRoot *string `json:"receipts_root"`
 }
 
-func (s *service) CastVote(input *CastVoteInput) error {
-	startTimeStr, _ := s.electionRepo.GetSetting("start_time")
-	endTimeStr, _ := s.electionRepo.GetSetting("end_time")
+type CandidateTally struct {
+	CandidateID int `json:"candidate_id"`
+	Votes       int `json:"votes"`
+}
 
-	if startTimeStr != "" && endTimeStr != "" {
-		startTime, err1 := time.Parse(time.RFC3339, startTimeStr)
-		endTime, err2 := time.Parse(time.RFC3339, endTimeStr)
-		if err1 == nil && err2 == nil {
-			now := time.Now().UTC()
-			startTime = startTime.UTC()
-			endTime = endTime.UTC()
+// LedgerExport is everything the offline verifier needs to replay a ledger
+// and check it against the published root and tallies.
+type LedgerExport struct {
+	ElectionID int              `json:"election_id"`
+	MerkleRoot *string          `json:"merkle_root"`
+	Tallies    []CandidateTally `json:"tallies"`
+	Entries    []ledger.Entry   `json:"entries"`
+}
 
-			if now.Before(startTime) || now.After(endTime) {
-				return errors.New("election is not currently active")
-			}
+type LedgerProof struct {
+	ElectionID int                `json:"election_id"`
+	Entry      ledger.Entry       `json:"entry"`
+	Steps      []ledger.ProofStep `json:"steps"`
+	MerkleRoot string             `json:"merkle_root"`
+}
+
+type DistrictResult struct {
+	District   *district.District    `json:"district"`
+	TotalVotes int                   `json:"total_votes"`
+	Candidates []candidate.Candidate `json:"candidates"`
+}
+
+func (s *service) CreateElection(input *CreateElectionInput) (*Election, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	election, err := buildElection(input.Name, input.Description, input.StartTime, input.EndTime, input.Threshold, input.PartyThreshold, input.BallotTitle, input.BallotOrder)
+	if err != nil {
+		return nil, err
+	}
+	election.Status = StatusDraft
+
+	id, err := s.electionRepo.Create(election)
+	if err != nil {
+		return nil, err
+	}
+	election.ID = int(id)
+	return election, nil
+}
+
+func (s *service) GetAllElections() ([]Election, error) {
+	return s.electionRepo.FindAll()
+}
+
+func (s *service) GetElectionByID(id int) (*Election, error) {
+	return s.findElection(id)
+}
+
+func (s *service) UpdateElection(id int, input *UpdateElectionInput) (*Election, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	election, err := buildElection(input.Name, input.Description, input.StartTime, input.EndTime, input.Threshold, input.PartyThreshold, input.BallotTitle, input.BallotOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.electionRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrElectionNotFound
+	}
+
+	switch existing.Status {
+	case StatusCertified:
+		return nil, ErrElectionCertified
+	case StatusOpen, StatusClosed:
+		if !sameTime(existing.StartTime, election.StartTime) || !sameTime(existing.EndTime, election.EndTime) ||
+			existing.BallotTitle != election.BallotTitle || existing.BallotOrder != election.BallotOrder ||
+			existing.Threshold != election.Threshold || existing.PartyThreshold != election.PartyThreshold {
+			return nil, ErrElectionLocked
+		}
+	case StatusScheduled:
+		if election.StartTime == nil {
+			return nil, ErrScheduleRequired
+		}
+	}
+
+	if err := s.electionRepo.Update(id, election); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrElectionNotFound
+		}
+		return nil, err
+	}
+	election.ID = id
+	election.Status = existing.Status
+	return election, nil
+}
+
+func (s *service) DeleteElection(id int) error {
+	existing, err := s.electionRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrElectionNotFound
+	}
+	if existing.Status != StatusDraft {
+		return ErrElectionNotDraft
+	}
+
+	hasCandidates, err := s.electionRepo.HasCandidates(id)
+	if err != nil {
+		return err
+	}
+	if hasCandidates {
+		return ErrElectionHasCandidates
+	}
+	err = s.electionRepo.Delete(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrElectionNotFound
+	}
+	return err
+}
+
+// GetBallot returns the ballot of a district: its own candidates plus those
+// not tied to any district. A zero districtID returns every candidate.
+func (s *service) GetBallot(electionID, districtID int) (*Ballot, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ballotDistrict *int
+	if districtID != 0 {
+		d, err := s.districtRepo.FindByID(districtID)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			return nil, ErrDistrictNotFound
+		}
+		ballotDistrict = &d.ID
+	}
+
+	all, err := s.candidateRepo.FindAll(election.ID, 0, 0, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]candidate.Candidate, 0, len(all))
+	for _, c := range all {
+		if ballotDistrict == nil || c.DistrictID == nil || *c.DistrictID == *ballotDistrict {
+			candidates = append(candidates, c)
+		}
+	}
+	sortBallot(candidates, election.BallotOrder)
+
+	title := election.BallotTitle
+	if title == "" {
+		title = election.Name
+	}
+
+	entries := make([]BallotEntry, 0, len(candidates))
+	for _, c := range candidates {
+		entries = append(entries, BallotEntry{
+			CandidateID: c.ID,
+			Name:        c.Name,
+			Party:       c.Party,
+		})
+	}
+
+	return &Ballot{
+		ElectionID: election.ID,
+		DistrictID: ballotDistrict,
+		Title:      title,
+		Entries:    entries,
+	}, nil
+}
+
+func (s *service) GetVoterBallot(electionID, userID int) (*Ballot, error) {
+	v, err := s.voterRepo.FindByUserID(userID)
+	if err != nil || v == nil {
+		return nil, ErrVoterNotFound
+	}
+	if v.DistrictID == nil {
+		return nil, ErrVoterWithoutDistrict
+	}
+	return s.GetBallot(electionID, *v.DistrictID)
+}
+
+func (s *service) CheckCandidateChanges(electionID int) error {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return err
+	}
+	if election.Status != StatusDraft && election.Status != StatusScheduled {
+		return ErrCandidatesLocked
+	}
+	return nil
+}
+
+func (s *service) GetElectionState(electionID int) (*ElectionState, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionState{
+		ElectionID:         election.ID,
+		Status:             election.Status,
+		AllowedTransitions: allowedTransitions(election.Status),
+	}, nil
+}
+
+func (s *service) GetTransitions(electionID int) ([]Transition, error) {
+	if _, err := s.findElection(electionID); err != nil {
+		return nil, err
+	}
+	return s.electionRepo.FindTransitions(electionID)
+}
+
+func (s *service) TransitionElection(electionID, userID int, canCertify bool, input *TransitionInput) (*ElectionState, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	if !isValidStatus(input.Status) {
+		return nil, ErrUnknownStatus
+	}
+
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+	if !canTransition(election.Status, input.Status) {
+		return nil, ErrInvalidTransition.WithParams(map[string]string{"from": election.Status, "to": input.Status})
+	}
+
+	hasCandidates, err := s.electionRepo.HasCandidates(electionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if err := checkTransition(election, input.Status, canCertify, hasCandidates, now); err != nil {
+		return nil, err
+	}
+
+	// Ballots still in the box of this process are stored before the
+	// election closes, rather than turned away once it has.
+	if input.Status == StatusClosed {
+		s.box.flush()
+	}
+
+	tx, err := s.electionRepo.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Votes take the same lock, so once it is held no vote is in flight and
+	// none can land before the status changes: closing sees every ballot
+	// that will ever be cast.
+	status, err := s.electionRepo.FindStatus(tx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	if status != election.Status {
+		return nil, ErrStatusChanged
+	}
+
+	if err := s.electionRepo.UpdateStatus(tx, electionID, election.Status, input.Status); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrStatusChanged
 		}
+		return nil, err
 	}
 
-	if input.VoterID == 0 || input.CandidateID == 0 {
-		return errors.New("voter_id and candidate_id are required")
+	transition := &Transition{
+		ElectionID: electionID,
+		FromStatus: election.Status,
+		ToStatus:   input.Status,
+		UserID:     userID,
+		Note:       input.Note,
+		CreatedAt:  now,
+	}
+	if err := s.electionRepo.CreateTransition(tx, transition); err != nil {
+		return nil, err
 	}
 
-	voter, err := s.voterRepo.FindByID(input.VoterID)
-	if err != nil || voter == nil {
-		return errors.New("voter not found")
+	// No ballot can be added once the election is closed, so this is the
+	// moment the ledger is built and sealed with its Merkle root.
+	if input.Status == StatusClosed {
+		if err := s.sealLedger(tx, electionID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The certified seats must not follow later changes to districts and
+	// parties, so they are stored as they are now.
+	if input.Status == StatusCertified {
+		allocation, err := s.allocateElection(election)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.electionRepo.CreateSeatAllocation(tx, allocation, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &ElectionState{
+		ElectionID:         electionID,
+		Status:             input.Status,
+		AllowedTransitions: allowedTransitions(input.Status),
+	}, nil
+}
+
+func (s *service) CastVote(userID int, canAssist bool, input *CastVoteInput) (*VoteReceipt, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+
+	voter, err := s.resolveVoter(userID, canAssist, input.VoterID)
+	if err != nil {
+		return nil, err
 	}
 
 	candidate, err := s.candidateRepo.FindByID(input.CandidateID)
 	if err != nil || candidate == nil {
-		return errors.New("candidate not found")
+		return nil, ErrCandidateNotFound
 	}
 
-	if voter.HasVoted {
-		return errors.New("voter has already voted")
+	if candidate.DistrictID != nil && (voter.DistrictID == nil || *voter.DistrictID != *candidate.DistrictID) {
+		return nil, ErrCandidateNotOnBallot
+	}
+
+	election, err := s.findElection(candidate.ElectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if election.Status != StatusOpen || (election.EndTime != nil && now.After(election.EndTime.UTC())) {
+		return nil, ErrElectionNotActive
 	}
 
 	tx, err := s.electionRepo.BeginTransaction()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer tx.Rollback()
 
-	if err := s.voterRepo.MarkAsVoted(tx, input.VoterID); err != nil {
-		return err
+	// The election may have been closed after it was read above; re-check
+	// with its row locked, which keeps it open until the vote is stored.
+	status, err := s.electionRepo.FindStatus(tx, election.ID)
+	if err != nil {
+		return nil, err
+	}
+	if status != StatusOpen {
+		return nil, ErrElectionNotActive
 	}
 
-	if err := s.candidateRepo.IncrementVoteCount(tx, input.CandidateID); err != nil {
-		return err
+	code, err := newReceiptCode()
+	if err != nil {
+		return nil, err
+	}
+	receiptHash := hashReceipt(code)
+	if err := s.electionRepo.CreateParticipation(tx, election.ID, voter.ID, receiptHash); err != nil {
+		return nil, err
 	}
 
-	if err := s.electionRepo.CreateVote(tx, input.VoterID, input.CandidateID); err != nil {
-		return err
+	if err := s.voterRepo.MarkAsVoted(tx, voter.ID); err != nil {
+		return nil, err
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// The ballot, and the counter of its candidate, are written by the
+	// ballot box in another transaction, so that nothing ties them to the
+	// participation stored above.
+	if err := s.box.cast(election.ID, input.CandidateID); err != nil {
+		if withdrawErr := s.withdrawVote(election.ID, voter.ID, receiptHash); withdrawErr != nil {
+			return nil, errors.Join(err, withdrawErr)
+		}
+		return nil, err
+	}
+	return &VoteReceipt{ElectionID: election.ID, Code: code}, nil
 }
 
-func (s *service) SetElectionTime(input *SetTimeInput) error {
-	_, err1 := time.Parse(time.RFC3339, input.StartTime)
-	_, err2 := time.Parse(time.RFC3339, input.EndTime)
-	if err1 != nil || err2 != nil {
-		return errors.New("invalid time format, use RFC3339 format (e.g., 2025-06-13T00:00:00Z)")
+// withdrawVote removes the participation and receipt of a vote whose ballot
+// could not be stored, so that the voter can vote again.
+func (s *service) withdrawVote(electionID, voterID int, receiptHash string) error {
+	tx, err := s.electionRepo.BeginTransaction()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	if err := s.electionRepo.SetSetting("start_time", input.StartTime); err != nil {
+	if err := s.electionRepo.DeleteParticipation(tx, electionID, voterID, receiptHash); err != nil {
 		return err
 	}
-	if err := s.electionRepo.SetSetting("end_time", input.EndTime); err != nil {
+	if err := s.voterRepo.UnmarkAsVoted(tx, voterID); err != nil {
 		return err
 	}
-	return nil
+	return tx.Commit()
+}
+
+// resolveVoter returns the voter linked to the authenticated user. Users who
+// can assist voters may name another voter explicitly to record an assisted
+// vote.
+func (s *service) resolveVoter(userID int, canAssist bool, voterID int) (*voter.Voter, error) {
+	if voterID != 0 {
+		if !canAssist {
+			return nil, ErrProxyVoteForbidden
+		}
+		v, err := s.voterRepo.FindByID(voterID)
+		if err != nil || v == nil {
+			return nil, ErrVoterNotFound
+		}
+		return v, nil
+	}
+
+	v, err := s.voterRepo.FindByUserID(userID)
+	if err != nil || v == nil {
+		return nil, ErrVoterNotFound
+	}
+	return v, nil
 }
 
-func (s *service) GetResults(qualifiedOnly bool) ([]candidate.Candidate, error) {
-	candidates, err := s.candidateRepo.FindAll("", "")
+func (s *service) GetResults(electionID, districtID int, qualifiedOnly bool) ([]candidate.Candidate, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.candidateRepo.FindAll(election.ID, districtID, 0, "", "")
 	if err != nil {
 		return nil, err
 	}
 
 	if qualifiedOnly {
-		thresholdStr, _ := s.electionRepo.GetSetting("threshold")
-		threshold, _ := strconv.Atoi(thresholdStr)
+		// The party threshold is measured on the whole election, not only on
+		// the district being looked at.
+		all := candidates
+		if districtID != 0 {
+			all, err = s.candidateRepo.FindAll(election.ID, 0, 0, "", "")
+			if err != nil {
+				return nil, err
+			}
+		}
+		passed := passedParties(tallyParties(all, election.PartyThreshold))
 
 		qualifiedCandidates := make([]candidate.Candidate, 0)
 		for _, c := range candidates {
-			if c.Votes >= threshold {
+			if passed[c.PartyID] && c.Votes >= election.Threshold {
 				qualifiedCandidates = append(qualifiedCandidates, c)
 			}
 		}
 		candidates = qualifiedCandidates
 	}
 
+	sortByVotes(candidates)
+	return candidates, nil
+}
+
+// GetDistrictResults groups the election results per district. Candidates
+// without a district are reported last under a nil district.
+func (s *service) GetDistrictResults(electionID int) ([]DistrictResult, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	districts, err := s.districtRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.candidateRepo.FindAll(election.ID, 0, 0, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]DistrictResult, 0, len(districts)+1)
+	indexByDistrict := make(map[int]int, len(districts))
+	for i := range districts {
+		indexByDistrict[districts[i].ID] = len(results)
+		results = append(results, DistrictResult{
+			District:   &districts[i],
+			Candidates: make([]candidate.Candidate, 0),
+		})
+	}
+
+	unassigned := DistrictResult{Candidates: make([]candidate.Candidate, 0)}
+	for _, c := range candidates {
+		target := &unassigned
+		if c.DistrictID != nil {
+			if idx, ok := indexByDistrict[*c.DistrictID]; ok {
+				target = &results[idx]
+			}
+		}
+		target.Candidates = append(target.Candidates, c)
+		target.TotalVotes += c.Votes
+	}
+	if len(unassigned.Candidates) > 0 {
+		results = append(results, unassigned)
+	}
+
+	for i := range results {
+		sortByVotes(results[i].Candidates)
+	}
+	return results, nil
+}
+
+// GetPartyResults reports every party's share of the election vote and
+// whether it reached the party threshold.
+func (s *service) GetPartyResults(electionID int) (*PartyResults, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.candidateRepo.FindAll(election.ID, 0, 0, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	parties := tallyParties(candidates, election.PartyThreshold)
+	totalVotes := 0
+	for _, p := range parties {
+		totalVotes += p.Votes
+	}
+	return &PartyResults{
+		ElectionID:     election.ID,
+		PartyThreshold: election.PartyThreshold,
+		TotalVotes:     totalVotes,
+		Parties:        parties,
+	}, nil
+}
+
+// GetSeatAllocation returns the seats stored when the election was certified
+// and, before that, allocates them from the current vote counts.
+func (s *service) GetSeatAllocation(electionID int) (*SeatAllocation, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+	if election.Status == StatusCertified {
+		allocation, err := s.electionRepo.FindSeatAllocation(election.ID)
+		if err != nil {
+			return nil, err
+		}
+		// Elections certified before allocations were stored have none.
+		if allocation != nil {
+			return allocation, nil
+		}
+	}
+	return s.allocateElection(election)
+}
+
+// allocateElection allocates the seats of every district from the current
+// vote counts. Only parties that passed the party threshold and candidates
+// that reached the candidate threshold can win seats; candidates without a
+// district do not take part.
+func (s *service) allocateElection(election *Election) (*SeatAllocation, error) {
+	districts, err := s.districtRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.candidateRepo.FindAll(election.ID, 0, 0, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	parties := tallyParties(candidates, election.PartyThreshold)
+	passed := passedParties(parties)
+
+	byDistrict := make(map[int][]candidate.Candidate)
+	for _, c := range candidates {
+		if c.DistrictID != nil {
+			byDistrict[*c.DistrictID] = append(byDistrict[*c.DistrictID], c)
+		}
+	}
+
+	allocation := &SeatAllocation{
+		ElectionID:     election.ID,
+		PartyThreshold: election.PartyThreshold,
+		Parties:        parties,
+		Districts:      make([]DistrictSeats, 0, len(districts)),
+	}
+	for i := range districts {
+		allocation.Districts = append(allocation.Districts, allocateSeats(&districts[i], byDistrict[districts[i].ID], passed, election.Threshold))
+	}
+	return allocation, nil
+}
+
+// VerifyResults checks that the published tallies still add up now that
+// participations and ballots are stored apart: there must be one ballot per
+// participation, and every candidate's counter must match its ballots. While
+// the election is open, votes whose ballot is still in the ballot box show up
+// as participations without a ballot.
+func (s *service) VerifyResults(electionID int) (*ResultsVerification, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.electionRepo.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// With the election row locked no vote or ballot lands between the
+	// counts below.
+	if _, err := s.electionRepo.FindStatus(tx, election.ID); err != nil {
+		return nil, err
+	}
+	participations, err := s.electionRepo.CountParticipations(tx, election.ID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.electionRepo.CountTallies(tx, election.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &ResultsVerification{
+		ElectionID:     election.ID,
+		Participations: participations,
+		Mismatches:     make([]CandidateMismatch, 0),
+	}
+	for _, c := range counts {
+		verification.Ballots += c.Ballots
+		verification.TalliedVotes += c.Tallied
+		if c.Tallied != c.Ballots {
+			verification.Mismatches = append(verification.Mismatches, CandidateMismatch{
+				CandidateID: c.CandidateID,
+				Tallied:     c.Tallied,
+				Ballots:     c.Ballots,
+			})
+		}
+	}
+	verification.Consistent = len(verification.Mismatches) == 0 &&
+		verification.Ballots == verification.Participations &&
+		verification.Ballots == verification.TalliedVotes
+	return verification, nil
+}
+
+// Recount recomputes every candidate's tally from the raw ballots and reports
+// each counter that does not match. With Repair set the counters are
+// overwritten with the ballot counts. Ballots whose candidate no longer exists
+// are reported but cannot be repaired. Every run is recorded for audit,
+// whether or not anything was found.
+func (s *service) Recount(electionID, userID int, input *RecountInput) (*Recount, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.electionRepo.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Votes lock the election row before they are written, so with the row
+	// locked here none can land until the recount is recorded.
+	status, err := s.electionRepo.FindStatus(tx, election.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The counters may only be overwritten once voting is over, and not
+	// under certified results.
+	if input.Repair {
+		if status == StatusCertified {
+			return nil, ErrElectionCertified
+		}
+		if status != StatusClosed {
+			return nil, ErrRepairNotClosed
+		}
+	}
+
+	counts, err := s.electionRepo.CountTallies(tx, election.ID)
+	if err != nil {
+		return nil, err
+	}
+	participations, err := s.electionRepo.CountParticipations(tx, election.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recount := &Recount{
+		ElectionID:     election.ID,
+		UserID:         userID,
+		Repair:         input.Repair,
+		Note:           input.Note,
+		Participations: participations,
+		Discrepancies:  make([]CandidateMismatch, 0),
+		CreatedAt:      time.Now().UTC(),
+	}
+	for _, c := range counts {
+		recount.Ballots += c.Ballots
+		recount.TalliedVotes += c.Tallied
+		if c.Tallied == c.Ballots {
+			continue
+		}
+		recount.Discrepancies = append(recount.Discrepancies, CandidateMismatch{
+			CandidateID: c.CandidateID,
+			Tallied:     c.Tallied,
+			Ballots:     c.Ballots,
+		})
+		if input.Repair && c.Exists {
+			if err := s.candidateRepo.SetVoteCount(tx, c.CandidateID, c.Ballots); err != nil {
+				return nil, err
+			}
+			recount.Repaired++
+		}
+	}
+
+	if err := s.electionRepo.CreateRecount(tx, recount); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return recount, nil
+}
+
+func (s *service) GetRecounts(electionID int) ([]Recount, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.electionRepo.FindRecounts(election.ID)
+}
+
+// LookupReceipt reports whether a receipt was issued and, once the election
+// has been closed, proves that it is in the Merkle tree of receipt hashes
+// published then.
+func (s *service) LookupReceipt(code string) (*ReceiptStatus, error) {
+	receiptHash := hashReceipt(code)
+	electionID, err := s.electionRepo.FindReceipt(receiptHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrReceiptNotFound
+		}
+		return nil, err
+	}
+
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.electionRepo.FindReceiptsRoot(election.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ReceiptStatus{
+		ElectionID:     election.ID,
+		ElectionStatus: election.Status,
+		Recorded:       true,
+		ReceiptHash:    receiptHash,
+		Steps:          make([]ledger.ProofStep, 0),
+		ReceiptsRoot:   root,
+	}
+	if root == nil {
+		return status, nil
+	}
+
+	tx, err := s.electionRepo.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	hashes, err := s.electionRepo.FindReceiptHashes(tx, election.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(hashes)
+	index := sort.SearchStrings(hashes, receiptHash)
+	if index == len(hashes) || hashes[index] != receiptHash {
+		return status, nil
+	}
+	steps, err := ledger.Proof(hashes, index)
+	if err != nil {
+		return nil, err
+	}
+	status.Steps = steps
+	status.Included = ledger.VerifyProof(receiptHash, steps, *root)
+	return status, nil
+}
+
+// sealLedger chains the ballots of the election into its ledger and publishes
+// the Merkle root over them. The ballots are shuffled first, so that the
+// order of the ledger says nothing about the order they were cast in, which
+// could be matched with the times voters were seen.
+//
+// The ballots are checked against the digest kept as they were stored first,
+// so that a ballot changed or added while the election was open is not
+// sealed in. The check cannot tell whether the digest was rewritten along
+// with the ballots.
+func (s *service) sealLedger(tx *database.Tx, electionID int) error {
+	ballots, err := s.electionRepo.FindBallots(tx, electionID)
+	if err != nil {
+		return err
+	}
+	digest, count, err := s.electionRepo.FindBallotsDigest(tx, electionID)
+	if err != nil {
+		return err
+	}
+	if digest != nil {
+		hashes := make([]string, 0, len(ballots))
+		for _, b := range ballots {
+			hashes = append(hashes, ledger.HashBallot(electionID, b.ID, b.CandidateID, b.CastDate))
+		}
+		stored, err := ledger.AddToDigest(ledger.EmptyDigest, hashes...)
+		if err != nil {
+			return err
+		}
+		if stored != *digest || len(ballots) != count {
+			return ErrBallotsTampered
+		}
+	}
+
+	if err := shuffle(len(ballots), func(i, j int) {
+		ballots[i], ballots[j] = ballots[j], ballots[i]
+	}); err != nil {
+		return err
+	}
+
+	entries := make([]ledger.Entry, 0, len(ballots))
+	hashes := make([]string, 0, len(ballots))
+	prevHash := ledger.GenesisHash
+	for i, b := range ballots {
+		entry := ledger.NewEntry(electionID, i+1, b.ID, b.CandidateID, b.CastDate, prevHash)
+		entries = append(entries, entry)
+		hashes = append(hashes, entry.Hash)
+		prevHash = entry.Hash
+	}
+	if err := s.electionRepo.CreateLedgerEntries(tx, entries); err != nil {
+		return err
+	}
+
+	root, err := ledger.MerkleRoot(hashes)
+	if err != nil {
+		return err
+	}
+	if err := s.electionRepo.SetLedgerRoot(tx, electionID, root, len(hashes)); err != nil {
+		return err
+	}
+
+	// The receipts get a tree of their own, sorted by hash, so that the
+	// proof given to a receipt holder has nothing to do with the place of
+	// their ballot in the ledger.
+	receiptHashes, err := s.electionRepo.FindReceiptHashes(tx, electionID)
+	if err != nil {
+		return err
+	}
+	sort.Strings(receiptHashes)
+	receiptsRoot, err := ledger.MerkleRoot(receiptHashes)
+	if err != nil {
+		return err
+	}
+	return s.electionRepo.SetReceiptsRoot(tx, electionID, receiptsRoot)
+}
+
+func (s *service) GetLedger(electionID int) (*LedgerSummary, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.electionRepo.FindLedger(election.ID)
+	if err != nil {
+		return nil, err
+	}
+	root, rootSize, err := s.electionRepo.FindLedgerRoot(election.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptsRoot, err := s.electionRepo.FindReceiptsRoot(election.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &LedgerSummary{
+		ElectionID:   election.ID,
+		Size:         len(entries),
+		HeadHash:     ledger.GenesisHash,
+		MerkleRoot:   root,
+		RootSize:     rootSize,
+		ReceiptsRoot: receiptsRoot,
+	}
+	if len(entries) > 0 {
+		summary.HeadHash = entries[len(entries)-1].Hash
+	}
+	return summary, nil
+}
+
+func (s *service) ExportLedger(electionID int) (*LedgerExport, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.electionRepo.FindLedger(election.ID)
+	if err != nil {
+		return nil, err
+	}
+	root, _, err := s.electionRepo.FindLedgerRoot(election.ID)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, ErrLedgerNotSealed
+	}
+	candidates, err := s.candidateRepo.FindAll(election.ID, 0, 0, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	tallies := make([]CandidateTally, 0, len(candidates))
+	for _, c := range candidates {
+		tallies = append(tallies, CandidateTally{CandidateID: c.ID, Votes: c.Votes})
+	}
+	return &LedgerExport{
+		ElectionID: election.ID,
+		MerkleRoot: root,
+		Tallies:    tallies,
+		Entries:    entries,
+	}, nil
+}
+
+// GetLedgerProof returns the inclusion proof of the seq-th ledger entry
+// against the published Merkle root.
+func (s *service) GetLedgerProof(electionID, seq int) (*LedgerProof, error) {
+	election, err := s.findElection(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	root, rootSize, err := s.electionRepo.FindLedgerRoot(election.ID)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, ErrLedgerNotSealed
+	}
+
+	entries, err := s.electionRepo.FindLedger(election.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) < rootSize {
+		return nil, ErrLedgerIncomplete
+	}
+	if seq < 1 || seq > rootSize {
+		return nil, ErrLedgerEntryNotFound
+	}
+
+	hashes := make([]string, 0, rootSize)
+	for _, e := range entries[:rootSize] {
+		hashes = append(hashes, e.Hash)
+	}
+	steps, err := ledger.Proof(hashes, seq-1)
+	if err != nil {
+		return nil, err
+	}
+	return &LedgerProof{
+		ElectionID: election.ID,
+		Entry:      entries[seq-1],
+		Steps:      steps,
+		MerkleRoot: *root,
+	}, nil
+}
+
+func sortByVotes(candidates []candidate.Candidate) {
 	n := len(candidates)
 	for i := 1; i < n; i++ {
 		key := candidates[i]
@@ -146,17 +1130,95 @@ func (s *service) GetResults(qualifiedOnly bool) ([]candidate.Candidate, error)
 		}
 		candidates[j+1] = key
 	}
+}
 
-	return candidates, nil
+func (s *service) findElection(id int) (*Election, error) {
+	election, err := s.electionRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if election == nil {
+		return nil, ErrElectionNotFound
+	}
+	return election, nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func buildElection(name, description, startTime, endTime string, threshold *int, partyThreshold *float64, ballotTitle, ballotOrder string) (*Election, error) {
+	start, err := parseOptionalTime(startTime)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseOptionalTime(endTime)
+	if err != nil {
+		return nil, err
+	}
+	if (start == nil) != (end == nil) {
+		return nil, ErrScheduleIncomplete
+	}
+	if start != nil && !end.After(*start) {
+		return nil, ErrEndBeforeStart
+	}
+
+	election := &Election{
+		Name:        name,
+		Description: description,
+		StartTime:   start,
+		EndTime:     end,
+		BallotTitle: ballotTitle,
+		BallotOrder: ballotOrder,
+	}
+
+	if threshold != nil {
+		election.Threshold = *threshold
+	}
+	if partyThreshold != nil {
+		election.PartyThreshold = *partyThreshold
+	}
+	if ballotOrder == "" {
+		election.BallotOrder = "id"
+	}
+
+	return election, nil
 }
 
-func (s *service) SetThreshold(input *SetThresholdInput) error {
-	if input.Threshold == nil {
-		return errors.New("threshold is required")
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
 	}
-	if *input.Threshold < 0 {
-		return errors.New("threshold must be a non-negative number")
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+func sortBallot(arr []candidate.Candidate, by string) {
+	n := len(arr)
+	for i := 1; i < n; i++ {
+		key := arr[i]
+		j := i - 1
+		for j >= 0 {
+			shouldMove := false
+			switch by {
+			case "name":
+				shouldMove = arr[j].Name > key.Name
+			case "party":
+				shouldMove = arr[j].Party.BallotNumber > key.Party.BallotNumber
+			}
+			if !shouldMove {
+				break
+			}
+			arr[j+1] = arr[j]
+			j = j - 1
+		}
+		arr[j+1] = key
 	}
-	thresholdStr := strconv.Itoa(*input.Threshold)
-	return s.electionRepo.SetSetting("threshold", thresholdStr)
 }
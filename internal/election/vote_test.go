@@ -0,0 +1,253 @@
+package election_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/ledger"
+	"legiskuy-backend/pkg/testserver"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// castVotes registers n voters in the district of the election, has each of
+// them vote for its candidate and returns their receipt codes.
+func castVotes(srv *testserver.Server, token string, e testserver.Election, n int) []string {
+	receipts := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		voterToken := srv.RegisterVoter(token, fmt.Sprintf("pemilih%d", i), e.DistrictID)
+		receipts = append(receipts, srv.CastVote(voterToken, e.CandidateID))
+	}
+	return receipts
+}
+
+// TestCastVoteOnce sends the votes of one voter all at once. Only one of
+// them may be counted, however the requests interleave.
+func TestCastVoteOnce(t *testing.T) {
+	const attempts = 10
+
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		e := srv.OpenElection(token)
+		voterToken := srv.RegisterVoter(token, "siti", e.DistrictID)
+
+		body, err := json.Marshal(map[string]int{"candidate_id": e.CandidateID})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The requests go to the app directly rather than through
+		// srv.Request, which would send them one after the other.
+		statuses := make(chan int, attempts)
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodPost, "/api/v1/votes", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+voterToken)
+				resp, err := srv.App.Test(req, -1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+				statuses <- resp.StatusCode
+			}()
+		}
+		wg.Wait()
+		close(statuses)
+
+		counts := make(map[int]int)
+		for status := range statuses {
+			counts[status]++
+		}
+		if counts[http.StatusOK] != 1 || counts[http.StatusConflict] != attempts-1 {
+			t.Fatalf("got responses %v, want one %d and %d times %d", counts, http.StatusOK, attempts-1, http.StatusConflict)
+		}
+
+		var results []struct {
+			ID    int `json:"id"`
+			Votes int `json:"votes"`
+		}
+		srv.Decode(srv.Request(http.MethodGet, fmt.Sprintf("/api/v1/elections/%d/results", e.ID), token, nil), &results)
+		if len(results) != 1 || results[0].Votes != 1 {
+			t.Fatalf("got results %+v, want one vote for candidate %d", results, e.CandidateID)
+		}
+	})
+}
+
+// TestBallotsUnlinkedFromVoters checks that no ballot, and no candidate
+// counter, was written by the transaction that stored a voter's
+// participation. PostgreSQL keeps that transaction in every row as xmin.
+func TestBallotsUnlinkedFromVoters(t *testing.T) {
+	srv := testserver.NewPostgres(t)
+	token := srv.Staff("petugas", rbac.RolePetugas)
+	e := srv.OpenElection(token)
+	castVotes(srv, token, e, 5)
+
+	for name, query := range map[string]string{
+		"ballots":    `SELECT COUNT(*) FROM participations p JOIN ballots b ON p.xmin = b.xmin`,
+		"candidates": `SELECT COUNT(*) FROM participations p JOIN candidates c ON p.xmin = c.xmin`,
+	} {
+		var linked int
+		if err := srv.DB.QueryRow(query).Scan(&linked); err != nil {
+			t.Fatal(err)
+		}
+		if linked != 0 {
+			t.Errorf("%d participations share their transaction with %s", linked, name)
+		}
+	}
+}
+
+// TestLedgerSealedAtClose checks that the ledger is only published once the
+// election is closed, and then holds every ballot under its Merkle root.
+func TestLedgerSealedAtClose(t *testing.T) {
+	const voters = 5
+
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		auditorToken := srv.Staff("auditor", "auditor")
+		e := srv.OpenElection(token)
+		castVotes(srv, token, e, voters)
+
+		path := fmt.Sprintf("/api/v1/elections/%d/ledger/entries", e.ID)
+		srv.Expect(srv.Request(http.MethodGet, path, auditorToken, nil), http.StatusConflict)
+
+		srv.Transition(token, e.ID, "closed")
+		srv.Expect(srv.Request(http.MethodGet, path, token, nil), http.StatusForbidden)
+		var export struct {
+			MerkleRoot string         `json:"merkle_root"`
+			Entries    []ledger.Entry `json:"entries"`
+		}
+		srv.Decode(srv.Request(http.MethodGet, path, auditorToken, nil), &export)
+		if len(export.Entries) != voters {
+			t.Fatalf("got %d ledger entries, want %d", len(export.Entries), voters)
+		}
+		if err := ledger.VerifyChain(export.Entries); err != nil {
+			t.Fatal(err)
+		}
+		hashes := make([]string, 0, len(export.Entries))
+		for _, entry := range export.Entries {
+			hashes = append(hashes, entry.Hash)
+		}
+		root, err := ledger.MerkleRoot(hashes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root != export.MerkleRoot {
+			t.Fatalf("got Merkle root %s, want %s", export.MerkleRoot, root)
+		}
+	})
+}
+
+// TestTamperedBallotsNotSealed checks that a ballot added to the database
+// while the election is open keeps it from being closed, instead of being
+// sealed into the ledger.
+func TestTamperedBallotsNotSealed(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		e := srv.OpenElection(token)
+		castVotes(srv, token, e, 2)
+
+		query := `INSERT INTO ballots (id, election_id, candidate_id, cast_date) VALUES ('tampered', ?, ?, '2025-06-13')`
+		if _, err := srv.DB.Exec(query, e.ID, e.CandidateID); err != nil {
+			t.Fatal(err)
+		}
+
+		resp := srv.Request(http.MethodPost, fmt.Sprintf("/api/v1/elections/%d/transitions", e.ID), token, map[string]string{"status": "closed"})
+		if resp.StatusCode != http.StatusInternalServerError {
+			resp.Body.Close()
+			t.Fatalf("got %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+		}
+		var body struct {
+			Code string `json:"code"`
+		}
+		srv.Decode(resp, &body)
+		if body.Code != "ballots_tampered" {
+			t.Fatalf("got code %q, want ballots_tampered", body.Code)
+		}
+
+		var state struct {
+			Status string `json:"status"`
+		}
+		srv.Decode(srv.Request(http.MethodGet, fmt.Sprintf("/api/v1/elections/%d/state", e.ID), token, nil), &state)
+		if state.Status != "open" {
+			t.Fatalf("got status %q, want the election still open", state.Status)
+		}
+	})
+}
+
+// TestLedgerProofIncomplete checks that a proof is refused with its own
+// error code when entries under the published root are missing.
+func TestLedgerProofIncomplete(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		auditorToken := srv.Staff("auditor", "auditor")
+		e := srv.OpenElection(token)
+		castVotes(srv, token, e, 3)
+		srv.Transition(token, e.ID, "closed")
+
+		if _, err := srv.DB.Exec(`DELETE FROM ledger_entries WHERE election_id = ? AND seq = 3`, e.ID); err != nil {
+			t.Fatal(err)
+		}
+		resp := srv.Request(http.MethodGet, fmt.Sprintf("/api/v1/elections/%d/ledger/proof?seq=1", e.ID), auditorToken, nil)
+		if resp.StatusCode != http.StatusInternalServerError {
+			resp.Body.Close()
+			t.Fatalf("got %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+		}
+		var body struct {
+			Code string `json:"code"`
+		}
+		srv.Decode(resp, &body)
+		if body.Code != "ledger_incomplete" {
+			t.Fatalf("got code %q, want ledger_incomplete", body.Code)
+		}
+	})
+}
+
+// TestReceiptProof checks that a receipt is proven to be in the receipts
+// root published at close, without pointing to its ballot.
+func TestReceiptProof(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		e := srv.OpenElection(token)
+		receipts := castVotes(srv, token, e, 3)
+
+		type receiptStatus struct {
+			Recorded     bool               `json:"recorded"`
+			Included     bool               `json:"included"`
+			ReceiptHash  string             `json:"receipt_hash"`
+			Steps        []ledger.ProofStep `json:"steps"`
+			ReceiptsRoot *string            `json:"receipts_root"`
+		}
+		var status receiptStatus
+		srv.Decode(srv.Request(http.MethodGet, "/api/v1/receipts/"+receipts[0], "", nil), &status)
+		if !status.Recorded || status.Included || status.ReceiptsRoot != nil {
+			t.Fatalf("got %+v before close, want recorded only", status)
+		}
+
+		srv.Transition(token, e.ID, "closed")
+		var summary struct {
+			ReceiptsRoot *string `json:"receipts_root"`
+		}
+		srv.Decode(srv.Request(http.MethodGet, fmt.Sprintf("/api/v1/elections/%d/ledger", e.ID), token, nil), &summary)
+		if summary.ReceiptsRoot == nil {
+			t.Fatal("no receipts root published at close")
+		}
+		for _, code := range receipts {
+			var status receiptStatus
+			srv.Decode(srv.Request(http.MethodGet, "/api/v1/receipts/"+code, "", nil), &status)
+			if !status.Included || status.ReceiptsRoot == nil || *status.ReceiptsRoot != *summary.ReceiptsRoot {
+				t.Fatalf("got %+v, want included under %s", status, *summary.ReceiptsRoot)
+			}
+			if !ledger.VerifyProof(status.ReceiptHash, status.Steps, *summary.ReceiptsRoot) {
+				t.Fatalf("proof of receipt %s does not verify", code)
+			}
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package lockout
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"net/http"
+)
+
+var (
+	// ErrTooManyAttempts is returned to a login of a username or from an
+	// address that has to wait. It says the same whether the username
+	// exists or not.
+	ErrTooManyAttempts = apperror.New(http.StatusTooManyRequests, "too_many_attempts", "too many failed logins, try again in {seconds}s")
+	ErrUnknownScope    = apperror.BadRequest("unknown_scope", "scope must be account or address")
+	ErrNotLocked       = apperror.NotFound("not_locked", "no failed logins of this username or address are counted")
+)
@@ -0,0 +1,100 @@
+package lockout
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/i18n"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// @Summary Get locked usernames and addresses
+// @Description Get the usernames and client addresses that have to wait before they can try to log in again, after failed logins. locked_out is true for those that reached the lockout rather than a short wait.
+// @Tags lockout
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param scope query string false "Only account (usernames) or address (client addresses)"
+// @Success 200 {array} Failures "Locked usernames and addresses"
+// @Failure 400 {object} apperror.Response "Bad request - unknown scope"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /lockouts [get]
+func (h *Handler) GetLocked(c *fiber.Ctx) error {
+	locked, err := h.service.GetLocked(c.Query("scope"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(locked)
+}
+
+// @Summary Unlock a username or an address
+// @Description Forget the failed logins of a username (scope account) or a client address (scope address), so that it can log in again at once
+// @Tags lockout
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param scope path string true "account or address"
+// @Param subject path string true "Username or address"
+// @Success 200 {object} map[string]string "Unlocked"
+// @Failure 400 {object} apperror.Response "Bad request - unknown scope"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage"
+// @Failure 404 {object} apperror.Response "Not found - no failed logins counted"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /lockouts/{scope}/{subject} [delete]
+func (h *Handler) Unlock(c *fiber.Ctx) error {
+	if err := h.service.Unlock(c.Params("scope"), c.Params("subject")); err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"message": i18n.Message(c, "unlocked", "Unlocked successfully"),
+	})
+}
+
+// @Summary Get login attempts
+// @Description Get the latest login attempts, successful or not, optionally only those of a username or from an address. result is succeeded, invalid_credentials, account_disabled, two_factor_pending (right password, code to follow), invalid_code (wrong two-factor or recovery code) or locked_out.
+// @Tags lockout
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param username query string false "Only attempts with this username"
+// @Param address query string false "Only attempts from this client address"
+// @Param limit query int false "Number of attempts, 100 by default and 1000 at most"
+// @Success 200 {array} Attempt "Login attempts, the latest first"
+// @Failure 400 {object} apperror.Response "Bad request - limit is not a positive number"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /login-attempts [get]
+func (h *Handler) GetAttempts(c *fiber.Ctx) error {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return apperror.Field("limit", "must_be_positive", "limit must be greater than zero")
+		}
+		limit = n
+	}
+
+	attempts, err := h.service.GetAttempts(AttemptFilter{
+		Username: c.Query("username"),
+		Address:  c.Query("address"),
+		Limit:    limit,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(attempts)
+}
@@ -0,0 +1,88 @@
+package lockout
+
+import "time"
+
+// Scopes failures are counted in.
+const (
+	// ScopeAccount counts the failures of a username.
+	ScopeAccount = "account"
+	// ScopeAddress counts the failures of a client IP address, whatever
+	// usernames it tries.
+	ScopeAddress = "address"
+)
+
+// Limit says how many failures of one subject are tolerated.
+type Limit struct {
+	// FreeFailures is how many failures can follow each other without
+	// having to wait. Each one after that doubles the wait, from a second.
+	FreeFailures int
+	// MaxFailures is the number of failures that locks the subject out for
+	// Lockout, and so does every failure after it.
+	MaxFailures int
+	// Lockout is how long a locked-out subject waits, and the longest any
+	// subject waits.
+	Lockout time.Duration
+	// Reset is how long after the last failure the failures are forgotten.
+	Reset time.Duration
+}
+
+// Policy holds the limits of both scopes. A zero Limit is replaced by its
+// default.
+type Policy struct {
+	Account Limit
+	Address Limit
+}
+
+// DefaultAccountLimit lets a user mistype their password a few times.
+var DefaultAccountLimit = Limit{
+	FreeFailures: 3,
+	MaxFailures:  10,
+	Lockout:      15 * time.Minute,
+	Reset:        24 * time.Hour,
+}
+
+// DefaultAddressLimit is looser than DefaultAccountLimit: many voters may
+// log in from the one address of a polling station.
+var DefaultAddressLimit = Limit{
+	FreeFailures: 20,
+	MaxFailures:  100,
+	Lockout:      15 * time.Minute,
+	Reset:        time.Hour,
+}
+
+func (p Policy) withDefaults() Policy {
+	if p.Account == (Limit{}) {
+		p.Account = DefaultAccountLimit
+	}
+	if p.Address == (Limit{}) {
+		p.Address = DefaultAddressLimit
+	}
+	return p
+}
+
+func (p Policy) limit(scope string) Limit {
+	if scope == ScopeAddress {
+		return p.Address
+	}
+	return p.Account
+}
+
+// wait returns how long a subject with failures has to wait after the last
+// of them.
+func (l Limit) wait(failures int) time.Duration {
+	if failures >= l.MaxFailures {
+		return l.Lockout
+	}
+	extra := failures - l.FreeFailures
+	if extra <= 0 {
+		return 0
+	}
+	if extra > 30 {
+		return l.Lockout
+	}
+	wait := time.Second << (extra - 1)
+	if wait > l.Lockout {
+		return l.Lockout
+	}
+	return wait
+}
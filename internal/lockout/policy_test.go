@@ -0,0 +1,46 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    Limit
+		failures int
+		want     time.Duration
+	}{
+		{"no failures", DefaultAccountLimit, 0, 0},
+		{"last free failure", DefaultAccountLimit, 3, 0},
+		{"first paid failure", DefaultAccountLimit, 4, time.Second},
+		{"doubles", DefaultAccountLimit, 5, 2 * time.Second},
+		{"doubles again", DefaultAccountLimit, 9, 32 * time.Second},
+		{"locked out", DefaultAccountLimit, 10, 15 * time.Minute},
+		{"stays locked out", DefaultAccountLimit, 11, 15 * time.Minute},
+		{"address free failures", DefaultAddressLimit, 20, 0},
+		{"address first paid failure", DefaultAddressLimit, 21, time.Second},
+		{"capped at lockout", Limit{FreeFailures: 0, MaxFailures: 100, Lockout: 10 * time.Second}, 5, 10 * time.Second},
+		{"no shift overflow", Limit{FreeFailures: 0, MaxFailures: 1000, Lockout: time.Hour}, 70, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := tt.limit.wait(tt.failures); got != tt.want {
+			t.Errorf("%s: wait(%d) = %s, want %s", tt.name, tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyDefaults(t *testing.T) {
+	custom := Limit{FreeFailures: 1, MaxFailures: 2, Lockout: time.Minute, Reset: time.Hour}
+	p := Policy{Address: custom}.withDefaults()
+	if p.Account != DefaultAccountLimit {
+		t.Errorf("got account limit %+v, want the default", p.Account)
+	}
+	if p.limit(ScopeAddress) != custom {
+		t.Errorf("got address limit %+v, want %+v", p.limit(ScopeAddress), custom)
+	}
+	if p.limit(ScopeAccount) != DefaultAccountLimit {
+		t.Errorf("got account limit %+v, want the default", p.limit(ScopeAccount))
+	}
+}
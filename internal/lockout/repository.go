@@ -0,0 +1,227 @@
+package lockout
+
+import (
+	"database/sql"
+	"legiskuy-backend/pkg/database"
+	"time"
+)
+
+// Results of a login attempt.
+const (
+	ResultSucceeded          = "succeeded"
+	ResultInvalidCredentials = "invalid_credentials"
+	ResultAccountDisabled    = "account_disabled"
+	// ResultTwoFactorPending is a right password of a user with two-factor
+	// authentication, whose login goes on with a code.
+	ResultTwoFactorPending = "two_factor_pending"
+	// ResultInvalidCode is a wrong code of the authenticator app or
+	// recovery code.
+	ResultInvalidCode = "invalid_code"
+	// ResultLockedOut is an attempt turned away without looking at the
+	// password, because the username or the address had to wait.
+	ResultLockedOut = "locked_out"
+)
+
+// Attempt is one login attempt.
+type Attempt struct {
+	ID       int    `json:"id"`
+	Username string `json:"username" example:"budi"`
+	// UserID is the account of Username, if there is one.
+	UserID      *int      `json:"user_id,omitempty"`
+	Address     string    `json:"address" example:"203.0.113.7"`
+	Result      string    `json:"result" example:"invalid_credentials"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// Failures are the recent failed logins of a username or an address.
+type Failures struct {
+	// Scope is account or address.
+	Scope string `json:"scope" example:"account"`
+	// Subject is the username or the address.
+	Subject      string    `json:"subject" example:"budi"`
+	Failures     int       `json:"failures" example:"10"`
+	LastFailedAt time.Time `json:"last_failed_at"`
+	// LockedUntil is when the subject may try to log in again.
+	LockedUntil time.Time `json:"locked_until"`
+	// LockedOut is whether the failures reached the lockout, rather than
+	// the subject only having to wait a little.
+	LockedOut bool `json:"locked_out"`
+}
+
+// AttemptFilter picks the attempts FindAttempts returns. Empty fields match
+// every attempt.
+type AttemptFilter struct {
+	Username string
+	Address  string
+	Limit    int
+}
+
+type Repository interface {
+	BeginTransaction() (*database.Tx, error)
+	CreateAttempt(attempt *Attempt) error
+	FindAttempts(filter AttemptFilter) ([]Attempt, error)
+
+	// AddFailure counts a failure of the subject at the time given, starting
+	// from none if the last was before resetBefore, and returns the failures
+	// with the count. Their row stays locked until tx ends.
+	AddFailure(tx *database.Tx, scope, subject string, at, resetBefore time.Time) (*Failures, error)
+	// RemoveFailure takes back one failure of the subject and returns the
+	// failures left, or nil if there are none.
+	RemoveFailure(tx *database.Tx, scope, subject string) (*Failures, error)
+	SetLockedUntil(tx *database.Tx, scope, subject string, until time.Time) error
+	// FindLocked returns the failures of the subjects that have to wait
+	// after now.
+	FindLocked(scope string, now time.Time) ([]Failures, error)
+	// DeleteFailures forgets the failures of a subject. It returns
+	// sql.ErrNoRows if there were none.
+	DeleteFailures(scope, subject string) error
+}
+
+type repository struct {
+	db *database.Database
+}
+
+func NewRepository(db *database.Database) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) BeginTransaction() (*database.Tx, error) {
+	return r.db.Begin()
+}
+
+func (r *repository) CreateAttempt(attempt *Attempt) error {
+	query := `INSERT INTO login_attempts (username, user_id, address, result, attempted_at) VALUES (?, ?, ?, ?, ?)`
+	id, err := r.db.Insert(query, attempt.Username, attempt.UserID, attempt.Address, attempt.Result, attempt.AttemptedAt)
+	if err != nil {
+		return err
+	}
+	attempt.ID = int(id)
+	return nil
+}
+
+// FindAttempts returns the attempts that match filter, the latest first.
+func (r *repository) FindAttempts(filter AttemptFilter) ([]Attempt, error) {
+	query := `SELECT id, username, user_id, address, result, attempted_at FROM login_attempts WHERE 1 = 1`
+	var args []interface{}
+	if filter.Username != "" {
+		query += ` AND username = ?`
+		args = append(args, filter.Username)
+	}
+	if filter.Address != "" {
+		query += ` AND address = ?`
+		args = append(args, filter.Address)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := make([]Attempt, 0)
+	for rows.Next() {
+		var a Attempt
+		if err := rows.Scan(&a.ID, &a.Username, &a.UserID, &a.Address, &a.Result, &a.AttemptedAt); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+const failuresColumns = `scope, subject, failures, last_failed_at, locked_until`
+
+func scanFailures(row interface{ Scan(...interface{}) error }) (*Failures, error) {
+	var f Failures
+	if err := row.Scan(&f.Scope, &f.Subject, &f.Failures, &f.LastFailedAt, &f.LockedUntil); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// AddFailure counts the failure in one statement, so that concurrent
+// failures of a subject are all counted. The statement locks the row, so a
+// concurrent transaction counting a failure of the same subject waits for tx
+// and then sees its count. locked_until is left as it was.
+func (r *repository) AddFailure(tx *database.Tx, scope, subject string, at, resetBefore time.Time) (*Failures, error) {
+	query := `INSERT INTO login_failures (scope, subject, failures, last_failed_at, locked_until) VALUES (?, ?, 1, ?, ?)
+		ON CONFLICT (scope, subject) DO UPDATE SET
+			failures = CASE WHEN login_failures.last_failed_at < ? THEN 1 ELSE login_failures.failures + 1 END,
+			last_failed_at = excluded.last_failed_at`
+	if _, err := tx.Exec(query, scope, subject, at, at, resetBefore); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT ` + failuresColumns + ` FROM login_failures WHERE scope = ? AND subject = ?`
+	return scanFailures(tx.QueryRow(query, scope, subject))
+}
+
+func (r *repository) RemoveFailure(tx *database.Tx, scope, subject string) (*Failures, error) {
+	query := `UPDATE login_failures SET failures = failures - 1 WHERE scope = ? AND subject = ? AND failures > 0`
+	if _, err := tx.Exec(query, scope, subject); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT ` + failuresColumns + ` FROM login_failures WHERE scope = ? AND subject = ?`
+	f, err := scanFailures(tx.QueryRow(query, scope, subject))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return f, err
+}
+
+func (r *repository) SetLockedUntil(tx *database.Tx, scope, subject string, until time.Time) error {
+	query := `UPDATE login_failures SET locked_until = ? WHERE scope = ? AND subject = ?`
+	_, err := tx.Exec(query, until, scope, subject)
+	return err
+}
+
+// FindLocked returns the failures of every scope if scope is empty.
+func (r *repository) FindLocked(scope string, now time.Time) ([]Failures, error) {
+	query := `SELECT ` + failuresColumns + ` FROM login_failures WHERE locked_until > ?`
+	args := []interface{}{now}
+	if scope != "" {
+		query += ` AND scope = ?`
+		args = append(args, scope)
+	}
+	query += ` ORDER BY locked_until DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locked := make([]Failures, 0)
+	for rows.Next() {
+		f, err := scanFailures(rows)
+		if err != nil {
+			return nil, err
+		}
+		locked = append(locked, *f)
+	}
+	return locked, rows.Err()
+}
+
+func (r *repository) DeleteFailures(scope, subject string) error {
+	query := `DELETE FROM login_failures WHERE scope = ? AND subject = ?`
+	return expectOneRow(r.db.Exec(query, scope, subject))
+}
+
+func expectOneRow(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package lockout slows down password guessing. It counts the failed logins
+// of each username and each client address, makes them wait longer after
+// each failure past a few and locks them out after many, and keeps the
+// history of every login attempt for petugas to review.
+package lockout
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	// Reserve counts a login attempt as a failure of the username and the
+	// address before the password or code is checked, or returns
+	// ErrTooManyAttempts if either has to wait before trying again.
+	Reserve(username, address string) error
+	// Record adds attempt, which Reserve let through unless it was locked
+	// out, to the history. A wrong password or code stays counted against
+	// its username and address. Other attempts are taken back, and a
+	// successful login forgets the failures of its username.
+	Record(attempt *Attempt) error
+	// Release takes back the attempt Reserve counted for a code that was
+	// right but is not a login, and so is not recorded.
+	Release(username, address string) error
+	// GetLocked returns the subjects of scope, or of both scopes if it is
+	// empty, that have to wait before trying again.
+	GetLocked(scope string) ([]Failures, error)
+	// Unlock forgets the failures of a subject, so it can log in at once.
+	Unlock(scope, subject string) error
+	GetAttempts(filter AttemptFilter) ([]Attempt, error)
+}
+
+const (
+	defaultAttemptsLimit = 100
+	maxAttemptsLimit     = 1000
+)
+
+type service struct {
+	repository Repository
+	policy     Policy
+}
+
+func NewService(repo Repository, policy Policy) Service {
+	return &service{
+		repository: repo,
+		policy:     policy.withDefaults(),
+	}
+}
+
+// Reserve counts the attempt before the password is compared rather than
+// after, so that attempts sent all at once cannot all pass the check before
+// any of them is counted. Counting locks the rows of the username and the
+// address, so the reservations of one subject take turns.
+func (s *service) Reserve(username, address string) error {
+	tx, err := s.repository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	var until time.Time
+	for _, subject := range [...]struct{ scope, subject string }{{ScopeAccount, username}, {ScopeAddress, address}} {
+		limit := s.policy.limit(subject.scope)
+		failures, err := s.repository.AddFailure(tx, subject.scope, subject.subject, now, now.Add(-limit.Reset))
+		if err != nil {
+			return err
+		}
+		if failures.LockedUntil.After(until) {
+			until = failures.LockedUntil
+		}
+		if err := s.repository.SetLockedUntil(tx, subject.scope, subject.subject, now.Add(limit.wait(failures.Failures))); err != nil {
+			return err
+		}
+	}
+	// The attempt is turned away, and its count rolled back, if either
+	// subject still had to wait.
+	if until.After(now) {
+		seconds := int(math.Ceil(until.Sub(now).Seconds()))
+		return ErrTooManyAttempts.WithParams(map[string]string{"seconds": strconv.Itoa(seconds)})
+	}
+	return tx.Commit()
+}
+
+func (s *service) Record(attempt *Attempt) error {
+	if attempt.AttemptedAt.IsZero() {
+		attempt.AttemptedAt = time.Now().UTC()
+	}
+	if err := s.repository.CreateAttempt(attempt); err != nil {
+		return err
+	}
+
+	switch attempt.Result {
+	case ResultSucceeded:
+		err := s.repository.DeleteFailures(ScopeAccount, attempt.Username)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		return s.takeBack(ScopeAddress, attempt.Address)
+	case ResultAccountDisabled, ResultTwoFactorPending:
+		return s.Release(attempt.Username, attempt.Address)
+	}
+	return nil
+}
+
+func (s *service) Release(username, address string) error {
+	if err := s.takeBack(ScopeAccount, username); err != nil {
+		return err
+	}
+	return s.takeBack(ScopeAddress, address)
+}
+
+// takeBack removes the failure Reserve counted for an attempt that was not
+// one, and shortens the wait of the subject to that of the failures left.
+func (s *service) takeBack(scope, subject string) error {
+	tx, err := s.repository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	failures, err := s.repository.RemoveFailure(tx, scope, subject)
+	if err != nil {
+		return err
+	}
+	if failures == nil {
+		return nil
+	}
+	until := failures.LastFailedAt.Add(s.policy.limit(scope).wait(failures.Failures))
+	if err := s.repository.SetLockedUntil(tx, scope, subject, until); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *service) GetLocked(scope string) ([]Failures, error) {
+	if scope != "" && scope != ScopeAccount && scope != ScopeAddress {
+		return nil, ErrUnknownScope
+	}
+	locked, err := s.repository.FindLocked(scope, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	for i := range locked {
+		locked[i].LockedOut = locked[i].Failures >= s.policy.limit(locked[i].Scope).MaxFailures
+	}
+	return locked, nil
+}
+
+func (s *service) Unlock(scope, subject string) error {
+	if scope != ScopeAccount && scope != ScopeAddress {
+		return ErrUnknownScope
+	}
+	err := s.repository.DeleteFailures(scope, subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotLocked
+	}
+	return err
+}
+
+func (s *service) GetAttempts(filter AttemptFilter) ([]Attempt, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAttemptsLimit
+	}
+	if filter.Limit > maxAttemptsLimit {
+		filter.Limit = maxAttemptsLimit
+	}
+	return s.repository.FindAttempts(filter)
+}
@@ -0,0 +1,11 @@
+package party
+
+import "legiskuy-backend/pkg/apperror"
+
+var (
+	ErrInvalidID     = apperror.InvalidID("party")
+	ErrPartyNotFound = apperror.NotFound("party_not_found", "Party not found")
+	ErrPartyTaken    = apperror.Conflict("party_taken", "Party name, abbreviation or ballot number already exists")
+	ErrPartyInUse    = apperror.Conflict("party_in_use", "party still has candidates")
+	ErrPartyLocked   = apperror.Conflict("party_locked", "party cannot be changed once an election it takes part in is closed")
+)
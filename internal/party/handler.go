@@ -0,0 +1,144 @@
+package party
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/i18n"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// @Summary Create a new party
+// @Description Create a new political party with its ballot number, logo and color
+// @Tags party
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param party body CreatePartyInput true "Party Data"
+// @Success 201 {object} Party "Party created successfully"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON or invalid party data"
+// @Failure 409 {object} apperror.Response "Conflict - party name, abbreviation or ballot number already exists"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /parties [post]
+func (h *Handler) CreateParty(c *fiber.Ctx) error {
+	input := new(CreatePartyInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	party, err := h.service.CreateParty(input)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(party)
+}
+
+// @Summary Get all parties
+// @Description Get all political parties ordered by ballot number
+// @Tags party
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} Party "List of parties"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /parties [get]
+func (h *Handler) GetAllParties(c *fiber.Ctx) error {
+	parties, err := h.service.GetAllParties()
+	if err != nil {
+		return err
+	}
+	return c.JSON(parties)
+}
+
+// @Summary Get party by ID
+// @Description Get a specific political party by its ID
+// @Tags party
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Party ID"
+// @Success 200 {object} Party "Party details"
+// @Failure 400 {object} apperror.Response "Bad request - invalid party ID"
+// @Failure 404 {object} apperror.Response "Not found - party not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /parties/{id} [get]
+func (h *Handler) GetPartyByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+	party, err := h.service.GetPartyByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(party)
+}
+
+// @Summary Update party
+// @Description Update a political party's details. A party cannot be changed once an election with candidates of the party is closed.
+// @Tags party
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Party ID"
+// @Param party body UpdatePartyInput true "Updated party data"
+// @Success 200 {object} Party "Updated party details"
+// @Failure 400 {object} apperror.Response "Bad request - invalid party ID, cannot parse JSON or invalid party data"
+// @Failure 404 {object} apperror.Response "Not found - party not found"
+// @Failure 409 {object} apperror.Response "Conflict - party name, abbreviation or ballot number already exists, or a closed election has candidates of the party"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /parties/{id} [put]
+func (h *Handler) UpdateParty(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+	input := new(UpdatePartyInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	party, err := h.service.UpdateParty(id, input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(party)
+}
+
+// @Summary Delete party
+// @Description Delete a political party that has no candidates
+// @Tags party
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Party ID"
+// @Success 200 {object} map[string]string "Party deleted successfully"
+// @Failure 400 {object} apperror.Response "Bad request - invalid party ID"
+// @Failure 404 {object} apperror.Response "Not found - party not found"
+// @Failure 409 {object} apperror.Response "Conflict - party still has candidates, or a closed election has candidates of the party"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /parties/{id} [delete]
+func (h *Handler) DeleteParty(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	err = h.service.DeleteParty(id)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": i18n.Message(c, "party_deleted", "Party deleted successfully"),
+	})
+}
@@ -0,0 +1,27 @@
+package party_test
+
+import (
+	"fmt"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/testserver"
+	"net/http"
+	"testing"
+)
+
+// TestPartyLockedAfterClose checks that a party, and with it the ballot order
+// and the party threshold, cannot change under the results of a closed
+// election.
+func TestPartyLockedAfterClose(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		token := srv.Staff("petugas", rbac.RolePetugas)
+		e := srv.OpenElection(token)
+
+		path := fmt.Sprintf("/api/v1/parties/%d", e.PartyID)
+		update := map[string]interface{}{"name": "Partai Satu", "abbreviation": "PS", "ballot_number": 2}
+		srv.Expect(srv.Request(http.MethodPut, path, token, update), http.StatusOK)
+
+		srv.Transition(token, e.ID, "closed")
+		srv.Expect(srv.Request(http.MethodPut, path, token, update), http.StatusConflict)
+		srv.Expect(srv.Request(http.MethodDelete, path, token, nil), http.StatusConflict)
+	})
+}
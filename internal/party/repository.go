@@ -0,0 +1,133 @@
+package party
+
+import (
+	"database/sql"
+	"legiskuy-backend/pkg/database"
+)
+
+type Party struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	BallotNumber int    `json:"ballot_number"`
+	LogoURL      string `json:"logo_url"`
+	Color        string `json:"color"`
+}
+
+type Repository interface {
+	Create(party *Party) (int64, error)
+	FindAll() ([]Party, error)
+	FindByID(id int) (*Party, error)
+	Update(id int, party *Party) error
+	Delete(id int) error
+	IsInUse(id int) (bool, error)
+	IsLocked(id int) (bool, error)
+}
+
+type repository struct {
+	db *database.Database
+}
+
+func NewRepository(db *database.Database) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(party *Party) (int64, error) {
+	query := `INSERT INTO parties (name, abbreviation, ballot_number, logo_url, color) VALUES (?, ?, ?, ?, ?)`
+	id, err := r.db.Insert(query, party.Name, party.Abbreviation, party.BallotNumber, party.LogoURL, party.Color)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *repository) FindAll() ([]Party, error) {
+	query := `SELECT id, name, abbreviation, ballot_number, logo_url, color FROM parties ORDER BY ballot_number`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parties := make([]Party, 0)
+	for rows.Next() {
+		var p Party
+		if err := rows.Scan(&p.ID, &p.Name, &p.Abbreviation, &p.BallotNumber, &p.LogoURL, &p.Color); err != nil {
+			return nil, err
+		}
+		parties = append(parties, p)
+	}
+	return parties, nil
+}
+
+func (r *repository) FindByID(id int) (*Party, error) {
+	query := `SELECT id, name, abbreviation, ballot_number, logo_url, color FROM parties WHERE id = ?`
+	row := r.db.QueryRow(query, id)
+
+	var p Party
+	if err := row.Scan(&p.ID, &p.Name, &p.Abbreviation, &p.BallotNumber, &p.LogoURL, &p.Color); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *repository) Update(id int, party *Party) error {
+	query := `UPDATE parties SET name = ?, abbreviation = ?, ballot_number = ?, logo_url = ?, color = ? WHERE id = ?`
+	result, err := r.db.Exec(query, party.Name, party.Abbreviation, party.BallotNumber, party.LogoURL, party.Color, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) Delete(id int) error {
+	query := `DELETE FROM parties WHERE id = ?`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) IsInUse(id int) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM candidates WHERE party_id = ?)`
+	var inUse bool
+	if err := r.db.QueryRow(query, id).Scan(&inUse); err != nil {
+		return false, err
+	}
+	return inUse, nil
+}
+
+// IsLocked reports whether a closed or certified election has candidates of
+// the party. Its results, the party threshold and the seats depend on the
+// party as it is.
+func (r *repository) IsLocked(id int) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM candidates c JOIN elections e ON e.id = c.election_id
+		WHERE c.party_id = ? AND e.status IN ('closed', 'certified')
+	)`
+	var locked bool
+	if err := r.db.QueryRow(query, id).Scan(&locked); err != nil {
+		return false, err
+	}
+	return locked, nil
+}
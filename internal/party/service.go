@@ -0,0 +1,136 @@
+package party
+
+import (
+	"database/sql"
+	"errors"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/validate"
+)
+
+type Service interface {
+	CreateParty(input *CreatePartyInput) (*Party, error)
+	GetAllParties() ([]Party, error)
+	GetPartyByID(id int) (*Party, error)
+	UpdateParty(id int, input *UpdatePartyInput) (*Party, error)
+	DeleteParty(id int) error
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{
+		repository: repo,
+	}
+}
+
+type CreatePartyInput struct {
+	Name         string `json:"name" validate:"trim,required,max=100"`
+	Abbreviation string `json:"abbreviation" validate:"trim,required,max=20"`
+	BallotNumber int    `json:"ballot_number" validate:"positive"`
+	LogoURL      string `json:"logo_url" validate:"trim,max=500"`
+	Color        string `json:"color" validate:"trim,hexcolor"`
+}
+
+type UpdatePartyInput struct {
+	Name         string `json:"name" validate:"trim,required,max=100"`
+	Abbreviation string `json:"abbreviation" validate:"trim,required,max=20"`
+	BallotNumber int    `json:"ballot_number" validate:"positive"`
+	LogoURL      string `json:"logo_url" validate:"trim,max=500"`
+	Color        string `json:"color" validate:"trim,hexcolor"`
+}
+
+func (s *service) CreateParty(input *CreatePartyInput) (*Party, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	party := buildParty(input.Name, input.Abbreviation, input.BallotNumber, input.LogoURL, input.Color)
+
+	id, err := s.repository.Create(party)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	party.ID = int(id)
+	return party, nil
+}
+
+func (s *service) GetAllParties() ([]Party, error) {
+	return s.repository.FindAll()
+}
+
+func (s *service) GetPartyByID(id int) (*Party, error) {
+	party, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if party == nil {
+		return nil, ErrPartyNotFound
+	}
+	return party, nil
+}
+
+func (s *service) UpdateParty(id int, input *UpdatePartyInput) (*Party, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	if err := s.checkLocked(id); err != nil {
+		return nil, err
+	}
+	partyToUpdate := buildParty(input.Name, input.Abbreviation, input.BallotNumber, input.LogoURL, input.Color)
+
+	if err := s.repository.Update(id, partyToUpdate); err != nil {
+		return nil, mapError(err)
+	}
+	partyToUpdate.ID = id
+	return partyToUpdate, nil
+}
+
+func (s *service) DeleteParty(id int) error {
+	if err := s.checkLocked(id); err != nil {
+		return err
+	}
+	inUse, err := s.repository.IsInUse(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrPartyInUse
+	}
+	return mapError(s.repository.Delete(id))
+}
+
+// checkLocked refuses changes to a party once an election it takes part in is
+// closed, so that its results and seats stay as they were counted.
+func (s *service) checkLocked(id int) error {
+	locked, err := s.repository.IsLocked(id)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrPartyLocked
+	}
+	return nil
+}
+
+// mapError turns the errors of a write to the parties table into the errors
+// of this package.
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrPartyNotFound
+	case database.IsUniqueViolation(err):
+		return ErrPartyTaken
+	}
+	return err
+}
+
+func buildParty(name, abbreviation string, ballotNumber int, logoURL, color string) *Party {
+	return &Party{
+		Name:         name,
+		Abbreviation: abbreviation,
+		BallotNumber: ballotNumber,
+		LogoURL:      logoURL,
+		Color:        color,
+	}
+}
@@ -0,0 +1,15 @@
+package rbac
+
+import "legiskuy-backend/pkg/apperror"
+
+var (
+	ErrRoleNotFound = apperror.NotFound("role_not_found", "role not found")
+	ErrRoleLocked   = apperror.Conflict("role_locked", "the permissions of super_admin cannot be changed")
+	// ErrNotGrantable is returned to a user who tries to give or take away
+	// permissions they do not hold themselves, through a role or its
+	// permissions.
+	ErrNotGrantable = apperror.Forbidden("not_grantable", "you cannot grant or revoke permissions you do not hold")
+	// ErrLastAdmin is returned when a change would leave no active user who
+	// can manage users.
+	ErrLastAdmin = apperror.Conflict("last_admin", "no active user would be left who can manage users")
+)
@@ -0,0 +1,85 @@
+package rbac
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// @Summary Get all roles
+// @Description Get every role with the permissions it grants
+// @Tags rbac
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} Role "List of roles"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /roles [get]
+func (h *Handler) GetAllRoles(c *fiber.Ctx) error {
+	roles, err := h.service.GetAllRoles()
+	if err != nil {
+		return err
+	}
+	return c.JSON(roles)
+}
+
+// @Summary Get all permissions
+// @Description Get every permission a role can grant
+// @Tags rbac
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} Permission "List of permissions"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /permissions [get]
+func (h *Handler) GetAllPermissions(c *fiber.Ctx) error {
+	permissions, err := h.service.GetAllPermissions()
+	if err != nil {
+		return err
+	}
+	return c.JSON(permissions)
+}
+
+// @Summary Set the permissions of a role
+// @Description Replace the permissions of a role. Only permissions the caller holds can be granted or revoked, the permissions of super_admin are fixed, and users:manage cannot be taken from the last role whose active users hold it.
+// @Tags rbac
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Param permissions body PermissionsInput true "New Permissions"
+// @Success 200 {object} Role "Permissions set successfully"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON or unknown permission"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires roles:manage, or a permission the caller does not hold"
+// @Failure 404 {object} apperror.Response "Not found - role not found"
+// @Failure 409 {object} apperror.Response "Conflict - role is super_admin, or no active user could manage users afterwards"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /roles/{name}/permissions [put]
+func (h *Handler) SetPermissions(c *fiber.Ctx) error {
+	input := new(PermissionsInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	role, err := h.service.SetPermissions(c.Params("name"), middleware.Permissions(c), input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(role)
+}
@@ -0,0 +1,46 @@
+// Package rbac holds the roles of the users and the permissions they grant.
+// Both live in the database: a user has one role, a role any number of
+// permissions, and routes and services check permissions, never roles.
+package rbac
+
+// The permissions the code checks. Each is a row of the permissions table; a
+// new one needs a migration that adds it and grants it to super_admin.
+const (
+	ManageUsers      = "users:manage"
+	ManageRoles      = "roles:manage"
+	ManageDistricts  = "districts:manage"
+	ManageParties    = "parties:manage"
+	ManageCandidates = "candidates:manage"
+	ManageVoters     = "voters:manage"
+	ManageElections  = "elections:manage"
+	CertifyElections = "elections:certify"
+	RunRecounts      = "recounts:run"
+	ReadRecounts     = "recounts:read"
+	ReadLedger       = "ledger:read"
+	ReadAnyBallot    = "ballots:read_any"
+	AssistVoters     = "votes:assist"
+)
+
+// Roles the code knows by name. Other roles are only known to the database.
+const (
+	// RoleSuperAdmin holds every permission, and its permissions cannot be
+	// changed, so that someone can always manage the roles.
+	RoleSuperAdmin = "super_admin"
+	RolePetugas    = "petugas"
+	// RolePemilih is the role of the voters who register themselves.
+	RolePemilih = "pemilih"
+)
+
+// Covers reports whether granted includes every permission of required.
+func Covers(granted, required []string) bool {
+	has := make(map[string]bool, len(granted))
+	for _, p := range granted {
+		has[p] = true
+	}
+	for _, p := range required {
+		if !has[p] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package rbac_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/testserver"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestLastAdminKept has the admins of two roles take users:manage away from
+// each other's role at the same moment. Only one of them may succeed, however
+// the requests interleave, so that someone can still manage users.
+func TestLastAdminKept(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		// Both roles get the same permissions, roles:manage included, so
+		// that each admin may change the other's role.
+		for _, query := range []string{
+			`INSERT INTO role_permissions (role, permission)
+				SELECT 'district_officer', permission FROM role_permissions
+				WHERE role = 'petugas' AND permission NOT IN (SELECT permission FROM role_permissions WHERE role = 'district_officer')`,
+			`INSERT INTO role_permissions (role, permission) VALUES ('petugas', 'roles:manage'), ('district_officer', 'roles:manage')`,
+		} {
+			if _, err := srv.DB.Exec(query); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Each role is changed by the admin holding the other one.
+		tokens := map[string]string{
+			"district_officer": srv.Staff("petugas", rbac.RolePetugas),
+			rbac.RolePetugas:   srv.Staff("officer", "district_officer"),
+		}
+
+		statuses := make(chan int, len(tokens))
+		var wg sync.WaitGroup
+		for role, token := range tokens {
+			wg.Add(1)
+			go func(role, token string) {
+				defer wg.Done()
+				body, err := json.Marshal(map[string][]string{"permissions": {rbac.ManageRoles}})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				// The requests go to the app directly rather than through
+				// srv.Request, which would send them one after the other.
+				req := httptest.NewRequest(http.MethodPut, "/api/v1/roles/"+role+"/permissions", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+token)
+				resp, err := srv.App.Test(req, -1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+				statuses <- resp.StatusCode
+			}(role, token)
+		}
+		wg.Wait()
+		close(statuses)
+
+		succeeded := 0
+		for status := range statuses {
+			if status == http.StatusOK {
+				succeeded++
+			}
+		}
+		if succeeded != 1 {
+			t.Fatalf("%d changes succeeded, want 1", succeeded)
+		}
+
+		var admins int
+		query := `SELECT COUNT(*) FROM users u JOIN role_permissions rp ON rp.role = u.role WHERE rp.permission = ? AND NOT u.disabled`
+		if err := srv.DB.QueryRow(query, rbac.ManageUsers).Scan(&admins); err != nil {
+			t.Fatal(err)
+		}
+		if admins != 1 {
+			t.Fatalf("got %d active admins, want 1", admins)
+		}
+	})
+}
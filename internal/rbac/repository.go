@@ -0,0 +1,164 @@
+package rbac
+
+import (
+	"database/sql"
+	"legiskuy-backend/pkg/database"
+)
+
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Repository interface {
+	BeginTransaction() (*database.Tx, error)
+	FindAllRoles() ([]Role, error)
+	FindRole(name string) (*Role, error)
+	FindAllPermissions() ([]Permission, error)
+	FindPermissions(role string) ([]string, error)
+	ReplacePermissions(tx *database.Tx, role string, permissions []string) error
+	LockActiveUsersWith(tx *database.Tx, permission string) error
+	CountActiveUsersWith(tx *database.Tx, permission, exceptRole string) (int, error)
+}
+
+type repository struct {
+	db *database.Database
+}
+
+func NewRepository(db *database.Database) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) BeginTransaction() (*database.Tx, error) {
+	return r.db.Begin()
+}
+
+func (r *repository) FindAllRoles() ([]Role, error) {
+	rows, err := r.db.Query(`SELECT name, description FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]Role, 0)
+	for rows.Next() {
+		var role Role
+		if err := rows.Scan(&role.Name, &role.Description); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range roles {
+		if roles[i].Permissions, err = r.FindPermissions(roles[i].Name); err != nil {
+			return nil, err
+		}
+	}
+	return roles, nil
+}
+
+func (r *repository) FindRole(name string) (*Role, error) {
+	var role Role
+	err := r.db.QueryRow(`SELECT name, description FROM roles WHERE name = ?`, name).Scan(&role.Name, &role.Description)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if role.Permissions, err = r.FindPermissions(name); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *repository) FindAllPermissions() ([]Permission, error) {
+	rows, err := r.db.Query(`SELECT name, description FROM permissions ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make([]Permission, 0)
+	for rows.Next() {
+		var p Permission
+		if err := rows.Scan(&p.Name, &p.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, rows.Err()
+}
+
+// FindPermissions returns the permissions of role, none for a role that does
+// not exist.
+func (r *repository) FindPermissions(role string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT permission FROM role_permissions WHERE role = ? ORDER BY permission`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make([]string, 0)
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, rows.Err()
+}
+
+func (r *repository) ReplacePermissions(tx *database.Tx, role string, permissions []string) error {
+	if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role = ?`, role); err != nil {
+		return err
+	}
+	for _, p := range permissions {
+		if _, err := tx.Exec(`INSERT INTO role_permissions (role, permission) VALUES (?, ?)`, role, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LockActiveUsersWith locks the rows of the users who are not disabled and
+// hold permission until tx ends, as the user repository does before it
+// demotes an admin, so that the two cannot leave no admin between them.
+// SQLite needs no row locks, as its transactions already take turns.
+func (r *repository) LockActiveUsersWith(tx *database.Tx, permission string) error {
+	query := `SELECT u.id FROM users u
+		JOIN role_permissions rp ON rp.role = u.role
+		WHERE rp.permission = ? AND NOT u.disabled` + tx.ForUpdate()
+	rows, err := tx.Query(query, permission)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	// The rows are read only so that their locks are taken.
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// CountActiveUsersWith counts, inside tx, the users who are not disabled and
+// hold permission through a role other than exceptRole.
+func (r *repository) CountActiveUsersWith(tx *database.Tx, permission, exceptRole string) (int, error) {
+	query := `SELECT COUNT(*) FROM users u
+		JOIN role_permissions rp ON rp.role = u.role
+		WHERE rp.permission = ? AND u.role <> ? AND NOT u.disabled`
+	var count int
+	err := tx.QueryRow(query, permission, exceptRole).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,115 @@
+package rbac
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/validate"
+)
+
+type Service interface {
+	GetAllRoles() ([]Role, error)
+	GetAllPermissions() ([]Permission, error)
+	// SetPermissions replaces the permissions of a role. grantor holds the
+	// permissions of whoever asks for it, who can only hand out what they
+	// have themselves.
+	SetPermissions(role string, grantor []string, input *PermissionsInput) (*Role, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{
+		repository: repo,
+	}
+}
+
+type PermissionsInput struct {
+	Permissions []string `json:"permissions" example:"recounts:read,ballots:read_any" validate:"required"`
+}
+
+func (s *service) GetAllRoles() ([]Role, error) {
+	return s.repository.FindAllRoles()
+}
+
+func (s *service) GetAllPermissions() ([]Permission, error) {
+	return s.repository.FindAllPermissions()
+}
+
+func (s *service) SetPermissions(name string, grantor []string, input *PermissionsInput) (*Role, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+
+	role, err := s.repository.FindRole(name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	if role.Name == RoleSuperAdmin {
+		return nil, ErrRoleLocked
+	}
+
+	known, err := s.repository.FindAllPermissions()
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(known))
+	for _, p := range known {
+		exists[p.Name] = true
+	}
+	permissions := make([]string, 0, len(input.Permissions))
+	seen := make(map[string]bool, len(input.Permissions))
+	for _, p := range input.Permissions {
+		if !exists[p] {
+			return nil, unknownPermission(p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			permissions = append(permissions, p)
+		}
+	}
+
+	if !Covers(grantor, role.Permissions) || !Covers(grantor, permissions) {
+		return nil, ErrNotGrantable
+	}
+	tx, err := s.repository.BeginTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// With the admins locked, no admin can be demoted or disabled between
+	// the count and the change.
+	if Covers(role.Permissions, []string{ManageUsers}) && !seen[ManageUsers] {
+		if err := s.repository.LockActiveUsersWith(tx, ManageUsers); err != nil {
+			return nil, err
+		}
+		others, err := s.repository.CountActiveUsersWith(tx, ManageUsers, role.Name)
+		if err != nil {
+			return nil, err
+		}
+		if others == 0 {
+			return nil, ErrLastAdmin
+		}
+	}
+
+	if err := s.repository.ReplacePermissions(tx, role.Name, permissions); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.repository.FindRole(role.Name)
+}
+
+func unknownPermission(permission string) *apperror.Error {
+	return apperror.Fields(apperror.FieldError{
+		Field:   "permissions",
+		Code:    "not_found",
+		Message: "unknown permission " + permission,
+		Params:  map[string]string{"permission": permission},
+	})
+}
@@ -0,0 +1,11 @@
+package user
+
+import "legiskuy-backend/pkg/apperror"
+
+var (
+	ErrInvalidID     = apperror.InvalidID("user")
+	ErrUserNotFound  = apperror.NotFound("user_not_found", "user not found")
+	ErrUsernameTaken = apperror.Conflict("username_taken", "username already exists")
+	ErrUnknownRole   = apperror.Field("role", "not_found", "role not found")
+	ErrVoterRole     = apperror.Field("role", "voter_role", "accounts with the pemilih role are made by registering")
+)
@@ -0,0 +1,175 @@
+package user
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/middleware"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// @Summary Create a staff account
+// @Description Create a new staff account, with the petugas role unless another is given. The caller must hold every permission of the role.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user body CreateUserInput true "Account Data"
+// @Success 201 {object} User "Account created successfully"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON, invalid account data or unknown role"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage and every permission of the role"
+// @Failure 409 {object} apperror.Response "Conflict - username already exists"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /users [post]
+func (h *Handler) CreateUser(c *fiber.Ctx) error {
+	input := new(CreateUserInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	user, err := h.service.CreateUser(middleware.Permissions(c), input)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(user)
+}
+
+// @Summary Get all users
+// @Description Get every user account, optionally only those with one role
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Only users with this role"
+// @Success 200 {array} User "List of users"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /users [get]
+func (h *Handler) GetAllUsers(c *fiber.Ctx) error {
+	users, err := h.service.GetAllUsers(c.Query("role"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(users)
+}
+
+// @Summary Get user by ID
+// @Description Get a specific user account by its ID
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} User "User details"
+// @Failure 400 {object} apperror.Response "Bad request - invalid user ID"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage"
+// @Failure 404 {object} apperror.Response "Not found - user not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /users/{id} [get]
+func (h *Handler) GetUserByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+	user, err := h.service.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(user)
+}
+
+// @Summary Change the role of a user
+// @Description Give a user another role. The caller must hold every permission of both the old and the new role, and the last active user who can manage users cannot lose that permission. The pemilih role cannot be given, as voter accounts are made by registering.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param role body ChangeRoleInput true "New Role"
+// @Success 200 {object} User "Role changed successfully"
+// @Failure 400 {object} apperror.Response "Bad request - invalid user ID, cannot parse JSON, unknown role or pemilih role"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage and every permission of both roles"
+// @Failure 404 {object} apperror.Response "Not found - user not found"
+// @Failure 409 {object} apperror.Response "Conflict - user is the last active user who can manage users"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /users/{id}/role [put]
+func (h *Handler) ChangeRole(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	input := new(ChangeRoleInput)
+	if err := c.BodyParser(input); err != nil {
+		return apperror.ErrInvalidJSON
+	}
+
+	user, err := h.service.ChangeRole(id, middleware.Permissions(c), input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(user)
+}
+
+// @Summary Disable a user
+// @Description Stop a user from logging in. Tokens they already hold stop working at once. The caller must hold every permission of the role of the user, and the last active user who can manage users cannot be disabled.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} User "User disabled successfully"
+// @Failure 400 {object} apperror.Response "Bad request - invalid user ID"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage and every permission of the role of the user"
+// @Failure 404 {object} apperror.Response "Not found - user not found"
+// @Failure 409 {object} apperror.Response "Conflict - user is the last active user who can manage users"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /users/{id}/disable [post]
+func (h *Handler) DisableUser(c *fiber.Ctx) error {
+	return h.setDisabled(c, true)
+}
+
+// @Summary Enable a user
+// @Description Let a disabled user log in again. The caller must hold every permission of the role of the user.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} User "User enabled successfully"
+// @Failure 400 {object} apperror.Response "Bad request - invalid user ID"
+// @Failure 401 {object} apperror.Response "Unauthorized - missing or invalid token"
+// @Failure 403 {object} apperror.Response "Forbidden - requires users:manage and every permission of the role of the user"
+// @Failure 404 {object} apperror.Response "Not found - user not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
+// @Router /users/{id}/enable [post]
+func (h *Handler) EnableUser(c *fiber.Ctx) error {
+	return h.setDisabled(c, false)
+}
+
+func (h *Handler) setDisabled(c *fiber.Ctx, disabled bool) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+	user, err := h.service.SetDisabled(id, middleware.Permissions(c), disabled)
+	if err != nil {
+		return err
+	}
+	return c.JSON(user)
+}
@@ -0,0 +1,186 @@
+package user
+
+import (
+	"database/sql"
+	"legiskuy-backend/pkg/database"
+	"time"
+)
+
+type User struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Disabled bool   `json:"disabled"`
+	Language string `json:"language,omitempty"`
+	// TwoFactor is whether the user turned on two-factor authentication.
+	TwoFactor bool `json:"two_factor"`
+}
+
+type Repository interface {
+	Create(user *User, passwordHash string) (int64, error)
+	FindAll(role string) ([]User, error)
+	FindByID(id int) (*User, error)
+	UpdateRole(id int, role string) error
+	UpdateDisabled(id int, disabled bool) error
+	CountActiveAdmins() (int, error)
+}
+
+type repository struct {
+	db *database.Database
+}
+
+func NewRepository(db *database.Database) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const userColumns = `id, name, username, role, disabled, COALESCE(language, ''),
+	EXISTS (SELECT 1 FROM totp_secrets t WHERE t.user_id = users.id AND t.confirmed_at IS NOT NULL)`
+
+func scanUser(row interface{ Scan(...interface{}) error }) (*User, error) {
+	var u User
+	if err := row.Scan(&u.ID, &u.Name, &u.Username, &u.Role, &u.Disabled, &u.Language, &u.TwoFactor); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *repository) Create(user *User, passwordHash string) (int64, error) {
+	query := `INSERT INTO users (name, username, password, role) VALUES (?, ?, ?, ?)`
+	return r.db.Insert(query, user.Name, user.Username, passwordHash, user.Role)
+}
+
+// FindAll returns every user with role, or every user if role is empty.
+func (r *repository) FindAll(role string) ([]User, error) {
+	query := `SELECT ` + userColumns + ` FROM users`
+	var args []interface{}
+	if role != "" {
+		query += ` WHERE role = ?`
+		args = append(args, role)
+	}
+	query += ` ORDER BY id`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]User, 0)
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *u)
+	}
+	return users, rows.Err()
+}
+
+func (r *repository) FindByID(id int) (*User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+	u, err := scanUser(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return u, err
+}
+
+// Admins are the users who can manage users: those whose role grants
+// users:manage.
+const adminRoles = `SELECT role FROM role_permissions WHERE permission = 'users:manage'`
+
+// keepsAdmin is the condition under which a user may stop being an active
+// admin: they are not one, or another one remains. The changes that check it
+// first lock the rows of the active admins with lockAdmins, so that two
+// admins cannot demote each other at the same moment and leave none.
+const keepsAdmin = `(role NOT IN (` + adminRoles + `) OR disabled OR EXISTS (
+	SELECT 1 FROM users AS other
+	WHERE other.role IN (` + adminRoles + `) AND NOT other.disabled AND other.id <> users.id
+))`
+
+// UpdateRole changes the role of a user. It returns sql.ErrNoRows if the user
+// does not exist or is the last active admin and role would change that.
+func (r *repository) UpdateRole(id int, role string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockAdmins(tx); err != nil {
+		return err
+	}
+	query := `UPDATE users SET role = ? WHERE id = ? AND (? IN (` + adminRoles + `) OR ` + keepsAdmin + `)`
+	if err := expectOneRow(tx.Exec(query, role, id, role)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateDisabled disables or enables a user. Disabling also ends their
+// sessions, so that enabling them again does not bring back the tokens they
+// held. It returns sql.ErrNoRows if the user does not exist or is the last
+// active admin and would be disabled.
+func (r *repository) UpdateDisabled(id int, disabled bool) error {
+	query := `UPDATE users SET disabled = ? WHERE id = ?`
+	if !disabled {
+		return expectOneRow(r.db.Exec(query, disabled, id))
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockAdmins(tx); err != nil {
+		return err
+	}
+	if err := expectOneRow(tx.Exec(query+` AND `+keepsAdmin, disabled, id)); err != nil {
+		return err
+	}
+	revoke := `UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	if _, err := tx.Exec(revoke, time.Now().UTC(), id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repository) CountActiveAdmins() (int, error) {
+	query := `SELECT COUNT(*) FROM users WHERE role IN (` + adminRoles + `) AND NOT disabled`
+	var count int
+	err := r.db.QueryRow(query).Scan(&count)
+	return count, err
+}
+
+// lockAdmins locks the rows of the active admins until tx ends. SQLite needs
+// no row locks, as its transactions already take turns.
+func lockAdmins(tx *database.Tx) error {
+	query := `SELECT id FROM users WHERE role IN (` + adminRoles + `) AND NOT disabled` + tx.ForUpdate()
+	rows, err := tx.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	// The rows are read only so that their locks are taken.
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+func expectOneRow(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
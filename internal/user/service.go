@@ -0,0 +1,173 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/validate"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Service manages the accounts of the staff. Voters create their own with
+// auth.Register.
+//
+// grantor holds the permissions of whoever creates, changes or disables an
+// account. They can only give, take away or disable a role whose permissions
+// they all hold themselves, so that nobody can make an account more powerful
+// than their own or lock out one that is.
+type Service interface {
+	CreateUser(grantor []string, input *CreateUserInput) (*User, error)
+	GetAllUsers(role string) ([]User, error)
+	GetUserByID(id int) (*User, error)
+	ChangeRole(id int, grantor []string, input *ChangeRoleInput) (*User, error)
+	SetDisabled(id int, grantor []string, disabled bool) (*User, error)
+	CountActiveAdmins() (int, error)
+}
+
+type service struct {
+	repository Repository
+	roleRepo   rbac.Repository
+}
+
+func NewService(repo Repository, roleRepo rbac.Repository) Service {
+	return &service{
+		repository: repo,
+		roleRepo:   roleRepo,
+	}
+}
+
+type CreateUserInput struct {
+	Name     string `json:"name" validate:"trim,required,max=100"`
+	Username string `json:"username" validate:"trim,required,min=3,max=32,username"`
+	// Password must be at least 8 characters long and contain both letters
+	// and digits.
+	Password string `json:"password" validate:"required,min=8,password"`
+	// Role defaults to petugas. It cannot be pemilih: voters register
+	// themselves, which links their account to a voter.
+	Role string `json:"role" example:"kpps" validate:"trim"`
+}
+
+type ChangeRoleInput struct {
+	Role string `json:"role" example:"petugas" validate:"trim,required"`
+}
+
+func (s *service) CreateUser(grantor []string, input *CreateUserInput) (*User, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	if input.Role == "" {
+		input.Role = rbac.RolePetugas
+	}
+	if input.Role == rbac.RolePemilih {
+		return nil, ErrVoterRole
+	}
+	if err := s.checkGrant(grantor, input.Role); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		Name:     input.Name,
+		Username: input.Username,
+		Role:     input.Role,
+	}
+	id, err := s.repository.Create(user, string(hashedPassword))
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, err
+	}
+	user.ID = int(id)
+	return user, nil
+}
+
+func (s *service) GetAllUsers(role string) ([]User, error) {
+	return s.repository.FindAll(role)
+}
+
+func (s *service) GetUserByID(id int) (*User, error) {
+	user, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *service) ChangeRole(id int, grantor []string, input *ChangeRoleInput) (*User, error) {
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	if input.Role == rbac.RolePemilih {
+		return nil, ErrVoterRole
+	}
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(grantor, user.Role); err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(grantor, input.Role); err != nil {
+		return nil, err
+	}
+	if err := s.refused(id, s.repository.UpdateRole(id, input.Role)); err != nil {
+		return nil, err
+	}
+	return s.GetUserByID(id)
+}
+
+func (s *service) SetDisabled(id int, grantor []string, disabled bool) (*User, error) {
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(grantor, user.Role); err != nil {
+		return nil, err
+	}
+	if err := s.refused(id, s.repository.UpdateDisabled(id, disabled)); err != nil {
+		return nil, err
+	}
+	return s.GetUserByID(id)
+}
+
+func (s *service) CountActiveAdmins() (int, error) {
+	return s.repository.CountActiveAdmins()
+}
+
+// checkGrant returns an error unless role exists and grantor holds all of
+// its permissions.
+func (s *service) checkGrant(grantor []string, role string) error {
+	found, err := s.roleRepo.FindRole(role)
+	if err != nil {
+		return err
+	}
+	if found == nil {
+		return ErrUnknownRole
+	}
+	if !rbac.Covers(grantor, found.Permissions) {
+		return rbac.ErrNotGrantable
+	}
+	return nil
+}
+
+// refused tells apart the two reasons the repository changes no row: the
+// user does not exist, or they are the last active admin.
+func (s *service) refused(id int, err error) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if _, err := s.GetUserByID(id); err != nil {
+		return err
+	}
+	return rbac.ErrLastAdmin
+}
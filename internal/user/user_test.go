@@ -0,0 +1,26 @@
+package user_test
+
+import (
+	"fmt"
+	"legiskuy-backend/internal/rbac"
+	"legiskuy-backend/pkg/testserver"
+	"net/http"
+	"testing"
+)
+
+// TestChangeRole checks that no user can be given the pemilih role, and that
+// the last active admin cannot give up managing users.
+func TestChangeRole(t *testing.T) {
+	testserver.Run(t, func(t *testing.T, srv *testserver.Server) {
+		admin := srv.CreateUser("petugas", testserver.Password, rbac.RolePetugas)
+		other := srv.CreateUser("saksi", testserver.Password, "saksi")
+		token := srv.Login("petugas", testserver.Password)
+
+		changeRole := func(id int, role string) *http.Response {
+			return srv.Request(http.MethodPut, fmt.Sprintf("/api/v1/users/%d/role", id), token, map[string]string{"role": role})
+		}
+		srv.Expect(changeRole(other.ID, rbac.RolePemilih), http.StatusBadRequest)
+		srv.Expect(changeRole(admin.ID, "saksi"), http.StatusConflict)
+		srv.Expect(changeRole(other.ID, "kpps"), http.StatusOK)
+	})
+}
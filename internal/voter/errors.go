@@ -0,0 +1,11 @@
+package voter
+
+import "legiskuy-backend/pkg/apperror"
+
+var (
+	ErrInvalidID        = apperror.InvalidID("voter")
+	ErrDistrictNotFound = apperror.Field("district_id", "not_found", "district not found")
+	ErrVoterNotFound    = apperror.NotFound("voter_not_found", "Voter not found")
+	ErrNameTaken        = apperror.Conflict("voter_name_taken", "Voter name already exists")
+	ErrAlreadyVoted     = apperror.Conflict("already_voted", "voter has already voted")
+)
@@ -1,9 +1,9 @@
 package voter
 
 import (
-	"database/sql"
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/i18n"
 	"strconv"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -19,39 +19,25 @@ func NewHandler(service Service) *Handler {
 }
 
 // @Summary Create a new voter
-// @Description Create a new voter with the provided name
+// @Description Create a new voter with the provided name and optional district
 // @Tags voter
 // @Accept json
 // @Produce json
 // @Param voter body CreateVoterInput true "Voter Data"
 // @Success 201 {object} map[string]interface{} "Voter created successfully"
-// @Failure 400 {object} map[string]string "Bad request - cannot parse JSON or name is required"
-// @Failure 409 {object} map[string]string "Conflict - voter name already exists"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - cannot parse JSON, name is required or district not found"
+// @Failure 409 {object} apperror.Response "Conflict - voter name already exists"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /voters [post]
 func (h *Handler) CreateVoter(c *fiber.Ctx) error {
 	input := new(CreateVoterInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
+		return apperror.ErrInvalidJSON
 	}
 
 	voter, err := h.service.CreateVoter(input)
 	if err != nil {
-		if err.Error() == "name is required" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error": "Voter name already exists",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create voter",
-		})
+		return err
 	}
 	return c.Status(fiber.StatusCreated).JSON(voter)
 }
@@ -62,16 +48,16 @@ func (h *Handler) CreateVoter(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param name query string false "Filter voters by name"
+// @Param district_id query int false "Filter voters by district ID"
 // @Success 200 {array} map[string]interface{} "List of voters"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /voters [get]
 func (h *Handler) GetAllVoters(c *fiber.Ctx) error {
 	name := c.Query("name")
-	voters, err := h.service.GetAllVoters(name)
+	districtID := c.QueryInt("district_id")
+	voters, err := h.service.GetAllVoters(name, districtID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get voters",
-		})
+		return err
 	}
 	return c.JSON(voters)
 }
@@ -83,27 +69,18 @@ func (h *Handler) GetAllVoters(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path int true "Voter ID"
 // @Success 200 {object} map[string]interface{} "Voter details"
-// @Failure 400 {object} map[string]string "Bad request - invalid voter ID"
-// @Failure 404 {object} map[string]string "Not found - voter not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - invalid voter ID"
+// @Failure 404 {object} apperror.Response "Not found - voter not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /voters/{id} [get]
 func (h *Handler) GetVoterByID(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid voter ID",
-		})
+		return ErrInvalidID
 	}
 	voter, err := h.service.GetVoterByID(id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get voter",
-		})
-	}
-	if voter == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Voter not found",
-		})
+		return err
 	}
 	return c.JSON(voter)
 }
@@ -116,45 +93,24 @@ func (h *Handler) GetVoterByID(c *fiber.Ctx) error {
 // @Param id path int true "Voter ID"
 // @Param voter body UpdateVoterInput true "Updated voter data"
 // @Success 200 {object} map[string]interface{} "Updated voter details"
-// @Failure 400 {object} map[string]string "Bad request - invalid voter ID or cannot parse JSON"
-// @Failure 404 {object} map[string]string "Not found - voter not found"
-// @Failure 409 {object} map[string]string "Conflict - voter name already exists"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - invalid voter ID, cannot parse JSON or district not found"
+// @Failure 404 {object} apperror.Response "Not found - voter not found"
+// @Failure 409 {object} apperror.Response "Conflict - voter name already exists"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /voters/{id} [put]
 func (h *Handler) UpdateVoter(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid voter ID",
-		})
+		return ErrInvalidID
 	}
 	input := new(UpdateVoterInput)
 	if err := c.BodyParser(input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON",
-		})
+		return apperror.ErrInvalidJSON
 	}
 
 	voter, err := h.service.UpdateVoter(id, input)
 	if err != nil {
-		if err.Error() == "name is required" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error": "Voter name already exists",
-			})
-		}
-		if err == sql.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Voter not found",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update voter",
-		})
+		return err
 	}
 	return c.JSON(voter)
 }
@@ -166,30 +122,21 @@ func (h *Handler) UpdateVoter(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path int true "Voter ID"
 // @Success 200 {object} map[string]string "Voter deleted successfully"
-// @Failure 400 {object} map[string]string "Bad request - invalid voter ID"
-// @Failure 404 {object} map[string]string "Not found - voter not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} apperror.Response "Bad request - invalid voter ID"
+// @Failure 404 {object} apperror.Response "Not found - voter not found"
+// @Failure 500 {object} apperror.Response "Internal server error"
 // @Router /voters/{id} [delete]
 func (h *Handler) DeleteVoter(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid voter ID",
-		})
+		return ErrInvalidID
 	}
 
 	err = h.service.DeleteVoter(id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Voter not found",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete voter",
-		})
+		return err
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Voter deleted successfully",
+		"message": i18n.Message(c, "voter_deleted", "Voter deleted successfully"),
 	})
 }
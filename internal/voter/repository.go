@@ -6,52 +6,67 @@ import (
 )
 
 type Voter struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	HasVoted bool   `json:"has_voted"`
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	HasVoted   bool   `json:"has_voted"`
+	UserID     *int   `json:"user_id,omitempty"`
+	DistrictID *int   `json:"district_id"`
 }
 
 type Repository interface {
 	Create(voter *Voter) (int64, error)
-	FindAll(name string) ([]Voter, error)
+	FindAll(name string, districtID int) ([]Voter, error)
 	FindByID(id int) (*Voter, error)
+	FindByUserID(userID int) (*Voter, error)
+	CreateForUser(tx *database.Tx, voter *Voter) (int64, error)
 	Update(id int, voter *Voter) error
 	Delete(id int) error
-	MarkAsVoted(tx *sql.Tx, VoterID int) error
+	MarkAsVoted(tx *database.Tx, VoterID int) error
+	UnmarkAsVoted(tx *database.Tx, voterID int) error
 }
 
 type repository struct {
-	db *sql.DB
+	db *database.Database
 }
 
-func NewRepository() Repository {
+func NewRepository(db *database.Database) Repository {
 	return &repository{
-		db: database.DB,
+		db: db,
 	}
 }
 
 func (r *repository) Create(voter *Voter) (int64, error) {
-	query := `INSERT INTO voters (name) VALUES (?)`
-	result, err := r.db.Exec(query, voter.Name)
+	query := `INSERT INTO voters (name, district_id) VALUES (?, ?)`
+	id, err := r.db.Insert(query, voter.Name, voter.DistrictID)
 	if err != nil {
 		return 0, err
 	}
-	id, err := result.LastInsertId()
+	return id, nil
+}
+
+func (r *repository) CreateForUser(tx *database.Tx, voter *Voter) (int64, error) {
+	query := `INSERT INTO voters (name, user_id) VALUES (?, ?)`
+	id, err := tx.Insert(query, voter.Name, voter.UserID)
 	if err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
-func (r *repository) FindAll(name string) ([]Voter, error) {
-	query := `SELECT id, name, has_voted FROM voters WHERE 1=1`
+func (r *repository) FindAll(name string, districtID int) ([]Voter, error) {
+	query := `SELECT id, name, has_voted, user_id, district_id FROM voters WHERE 1=1`
 	args := []interface{}{}
 
 	if name != "" {
-		query += " AND name LIKE ?"
+		query += " AND LOWER(name) LIKE LOWER(?)"
 		args = append(args, "%"+name+"%")
 	}
 
+	if districtID != 0 {
+		query += " AND district_id = ?"
+		args = append(args, districtID)
+	}
+
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -60,32 +75,42 @@ func (r *repository) FindAll(name string) ([]Voter, error) {
 
 	var voters []Voter
 	for rows.Next() {
-		var v Voter
-		if err := rows.Scan(&v.ID, &v.Name, &v.HasVoted); err != nil {
+		v, err := scanVoter(rows)
+		if err != nil {
 			return nil, err
 		}
-		voters = append(voters, v)
+		voters = append(voters, *v)
 	}
 	return voters, nil
 }
 
 func (r *repository) FindByID(id int) (*Voter, error) {
-	query := `SELECT id, name, has_voted FROM voters WHERE id = ?`
-	row := r.db.QueryRow(query, id)
+	query := `SELECT id, name, has_voted, user_id, district_id FROM voters WHERE id = ?`
+	v, err := scanVoter(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return v, nil
+}
 
-	var v Voter
-	if err := row.Scan(&v.ID, &v.Name, &v.HasVoted); err != nil {
+func (r *repository) FindByUserID(userID int) (*Voter, error) {
+	query := `SELECT id, name, has_voted, user_id, district_id FROM voters WHERE user_id = ?`
+	v, err := scanVoter(r.db.QueryRow(query, userID))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &v, nil
+	return v, nil
 }
 
 func (r *repository) Update(id int, voter *Voter) error {
-	query := `UPDATE voters SET name = ? WHERE id = ?`
-	result, err := r.db.Exec(query, voter.Name, id)
+	query := `UPDATE voters SET name = ?, district_id = ? WHERE id = ?`
+	result, err := r.db.Exec(query, voter.Name, voter.DistrictID, id)
 	if err != nil {
 		return err
 	}
@@ -115,8 +140,49 @@ func (r *repository) Delete(id int) error {
 	return nil
 }
 
-func (r *repository) MarkAsVoted(tx *sql.Tx, voterID int) error {
+// MarkAsVoted records that the voter has voted in at least one election. The
+// one-vote-per-election rule is enforced by the participations primary key.
+func (r *repository) MarkAsVoted(tx *database.Tx, voterID int) error {
 	query := `UPDATE voters SET has_voted = TRUE WHERE id = ?`
-	_, err := tx.Exec(query, voterID)
+	result, err := tx.Exec(query, voterID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UnmarkAsVoted undoes MarkAsVoted for a vote that was withdrawn, unless the
+// voter still took part in another election.
+func (r *repository) UnmarkAsVoted(tx *database.Tx, voterID int) error {
+	query := `UPDATE voters SET has_voted = EXISTS (SELECT 1 FROM participations WHERE voter_id = ?) WHERE id = ?`
+	_, err := tx.Exec(query, voterID, voterID)
 	return err
 }
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVoter(row scanner) (*Voter, error) {
+	var v Voter
+	var userID, districtID sql.NullInt64
+	if err := row.Scan(&v.ID, &v.Name, &v.HasVoted, &userID, &districtID); err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		id := int(userID.Int64)
+		v.UserID = &id
+	}
+	if districtID.Valid {
+		id := int(districtID.Int64)
+		v.DistrictID = &id
+	}
+	return &v, nil
+}
@@ -1,75 +1,124 @@
 package voter
 
 import (
+	"database/sql"
 	"errors"
+	"legiskuy-backend/internal/district"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/validate"
 )
 
 type Service interface {
 	CreateVoter(input *CreateVoterInput) (*Voter, error)
-	GetAllVoters(name string) ([]Voter, error)
+	GetAllVoters(name string, districtID int) ([]Voter, error)
 	GetVoterByID(id int) (*Voter, error)
 	UpdateVoter(id int, input *UpdateVoterInput) (*Voter, error)
 	DeleteVoter(id int) error
 }
 
 type service struct {
-	repository Repository
+	repository   Repository
+	districtRepo district.Repository
 }
 
-func NewService(repo Repository) Service {
+func NewService(repo Repository, districtRepo district.Repository) Service {
 	return &service{
-		repository: repo,
+		repository:   repo,
+		districtRepo: districtRepo,
 	}
 }
 
 type CreateVoterInput struct {
-	Name string `json:"name"`
+	Name       string `json:"name" validate:"trim,required,max=100"`
+	DistrictID *int   `json:"district_id"`
 }
 
 type UpdateVoterInput struct {
-	Name string `json:"name"`
+	Name       string `json:"name" validate:"trim,required,max=100"`
+	DistrictID *int   `json:"district_id"`
 }
 
 func (s *service) CreateVoter(input *CreateVoterInput) (*Voter, error) {
-	if input.Name == "" {
-		return nil, errors.New("name is required")
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	if err := s.checkDistrict(input.DistrictID); err != nil {
+		return nil, err
 	}
 
 	voter := &Voter{
-		Name: input.Name,
+		Name:       input.Name,
+		DistrictID: input.DistrictID,
 	}
 	id, err := s.repository.Create(voter)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 	voter.ID = int(id)
 	return voter, nil
 }
 
-func (s *service) GetAllVoters(name string) ([]Voter, error) {
-	return s.repository.FindAll(name)
+func (s *service) GetAllVoters(name string, districtID int) ([]Voter, error) {
+	return s.repository.FindAll(name, districtID)
 }
 
 func (s *service) GetVoterByID(id int) (*Voter, error) {
-	return s.repository.FindByID(id)
+	voter, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if voter == nil {
+		return nil, ErrVoterNotFound
+	}
+	return voter, nil
 }
 
 func (s *service) UpdateVoter(id int, input *UpdateVoterInput) (*Voter, error) {
-	if input.Name == "" {
-		return nil, errors.New("name is required")
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+	if err := s.checkDistrict(input.DistrictID); err != nil {
+		return nil, err
 	}
 
 	voterToUpdate := &Voter{
-		Name: input.Name,
+		Name:       input.Name,
+		DistrictID: input.DistrictID,
 	}
 	err := s.repository.Update(id, voterToUpdate)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 	voterToUpdate.ID = id
 	return voterToUpdate, nil
 }
 
 func (s *service) DeleteVoter(id int) error {
-	return s.repository.Delete(id)
+	return mapError(s.repository.Delete(id))
+}
+
+func (s *service) checkDistrict(districtID *int) error {
+	if districtID == nil {
+		return nil
+	}
+	d, err := s.districtRepo.FindByID(*districtID)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return ErrDistrictNotFound
+	}
+	return nil
+}
+
+// mapError turns the errors of a write to the voters table into the errors of
+// this package.
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrVoterNotFound
+	case database.IsUniqueViolation(err):
+		return ErrNameTaken
+	}
+	return err
 }
@@ -0,0 +1,154 @@
+// Package apperror defines the errors services return to say what went wrong
+// in terms a client can act on. An Error carries the HTTP status it maps to and
+// a machine-readable code; middleware.ErrorHandler turns it into a Response.
+//
+// Services declare the errors they return as package variables, so that
+// callers can tell them apart with errors.Is:
+//
+//	var ErrDistrictNotFound = apperror.NotFound("district_not_found", "District not found")
+package apperror
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Codes shared by errors of every package.
+const (
+	CodeValidation   = "validation_failed"
+	CodeInvalidJSON  = "invalid_json"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeInternal     = "internal_error"
+)
+
+type Error struct {
+	Status int
+	Code   string
+	// Message is the English message. Placeholders such as {from} are
+	// filled in from Params.
+	Message string
+	Params  map[string]string
+	// Fields lists the input fields that are wrong, if the error is about
+	// the input.
+	Fields []FieldError
+
+	// sentinel is the error this one was made from by WithParams.
+	sentinel *Error
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Params fills the placeholders of the message other than {field} when
+	// it is translated.
+	Params map[string]string `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return Format(e.Message, e.Params)
+}
+
+// Is reports whether e is target or was made from it by WithParams.
+func (e *Error) Is(target error) bool {
+	return e.sentinel != nil && e.sentinel == target
+}
+
+// WithParams returns a copy of e with the placeholders of its message filled
+// in. The copy is still e as far as errors.Is is concerned.
+func (e *Error) WithParams(params map[string]string) *Error {
+	copied := *e
+	copied.Params = params
+	copied.sentinel = e
+	if e.sentinel != nil {
+		copied.sentinel = e.sentinel
+	}
+	return &copied
+}
+
+// Format fills the {name} placeholders of message with params.
+func Format(message string, params map[string]string) string {
+	for name, value := range params {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+	return message
+}
+
+// Response is the body of every error response.
+type Response struct {
+	Error     string       `json:"error" example:"name is required"`
+	Code      string       `json:"code" example:"validation_failed"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty" example:"3f1c9a52-7c0e-4b8e-9d53-0c1f0f4a6b2e"`
+}
+
+var (
+	ErrInvalidJSON  = BadRequest(CodeInvalidJSON, "Cannot parse JSON")
+	ErrUnauthorized = New(http.StatusUnauthorized, CodeUnauthorized, "Unauthorized")
+	ErrForbidden    = New(http.StatusForbidden, CodeForbidden, "Forbidden: insufficient permissions")
+	ErrInternal     = New(http.StatusInternalServerError, CodeInternal, "Internal server error")
+	// ErrAccountDisabled is returned to a user whose account a petugas
+	// disabled, whether they log in or use a token they already hold.
+	ErrAccountDisabled = Forbidden("account_disabled", "account is disabled")
+)
+
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(code, message string) *Error {
+	return New(http.StatusBadRequest, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return New(http.StatusNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(http.StatusConflict, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(http.StatusForbidden, code, message)
+}
+
+// Internal returns an error that is not the client's fault but that the
+// client is told about, such as stored data failing an integrity check.
+func Internal(code, message string) *Error {
+	return New(http.StatusInternalServerError, code, message)
+}
+
+// Field returns a validation error about one input field. code says what is
+// wrong with it, such as "required".
+func Field(field, code, message string) *Error {
+	return Fields(FieldError{Field: field, Code: code, Message: message})
+}
+
+// Required returns the validation error of a missing field.
+func Required(field string) *Error {
+	return Field(field, "required", field+" is required")
+}
+
+// Fields returns a validation error about several input fields at once.
+func Fields(fields ...FieldError) *Error {
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Message
+	}
+	return &Error{
+		Status:  http.StatusBadRequest,
+		Code:    CodeValidation,
+		Message: strings.Join(messages, "; "),
+		Fields:  fields,
+	}
+}
+
+var errInvalidID = BadRequest("invalid_id", "Invalid {resource} ID")
+
+// InvalidID returns the error of a path parameter that is not an ID.
+func InvalidID(resource string) *Error {
+	return errInvalidID.WithParams(map[string]string{"resource": resource})
+}
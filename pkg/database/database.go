@@ -1,88 +1,133 @@
 package database
 
 import (
+	"crypto/rand"
 	"database/sql"
-	"log"
+	"encoding/hex"
+	"errors"
+	"strings"
 
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 )
 
-var DB *sql.DB
+// Database is a connection pool that rebinds the ? placeholders of every
+// query for its dialect.
+type Database struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+// Tx is a transaction on a Database.
+type Tx struct {
+	*sql.Tx
+	dialect Dialect
+}
 
-func ConnectDB() {
-	var err error
+func New(db *sql.DB, dialect Dialect) *Database {
+	return &Database{DB: db, Dialect: dialect}
+}
 
-	DB, err = sql.Open("sqlite3", "./legiskuy.db")
+func (db *Database) Begin() (*Tx, error) {
+	tx, err := db.DB.Begin()
 	if err != nil {
-		log.Fatal("Gagal terhubung ke database:", err)
+		return nil, err
 	}
+	return &Tx{Tx: tx, dialect: db.Dialect}, nil
+}
 
-	if err = DB.Ping(); err != nil {
-		log.Fatal("Gagal ping ke database:", err)
-	}
+func (db *Database) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.Exec(db.Dialect.Rebind(query), args...)
+}
 
-	log.Println("Berhasil terhubung ke database.")
-
-	createTables()
-}
-
-func createTables() {
-	candidatesTable := `
-	CREATE TABLE IF NOT EXISTS candidates (
-		"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
-		"name" TEXT NOT NULL,
-		"party" TEXT NOT NULL,
-		"votes" INTEGER DEFAULT 0
-	);`
-
-	votersTable := `
-    CREATE TABLE IF NOT EXISTS voters (
-        "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
-        "name" TEXT NOT NULL,
-        "has_voted" BOOLEAN DEFAULT FALSE
-    );`
-
-	votesTable := `
-	CREATE TABLE IF NOT EXISTS votes (
-		"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
-		"voter_id" INTEGER,
-		"candidate_id" INTEGER,
-		"created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY(voter_id) REFERENCES voters(id),
-		FOREIGN KEY(candidate_id) REFERENCES candidates(id)
-	);`
-
-	settingsTable := `
-	CREATE TABLE IF NOT EXISTS settings (
-		"key" TEXT NOT NULL PRIMARY KEY,
-		"value" TEXT
-	);`
-
-	usersTable := `
-    CREATE TABLE IF NOT EXISTS users (
-        "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
-        "name" TEXT NOT NULL,
-        "username" TEXT NOT NULL UNIQUE,
-        "password" TEXT NOT NULL,
-        "role" TEXT NOT NULL,
-        "has_voted" BOOLEAN DEFAULT FALSE
-    );`
-
-	if _, err := DB.Exec(candidatesTable); err != nil {
-		log.Fatal("Gagal membuat tabel candidates:", err)
-	}
-	if _, err := DB.Exec(votersTable); err != nil {
-		log.Fatal("Gagal membuat tabel voters:", err)
+func (db *Database) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.Query(db.Dialect.Rebind(query), args...)
+}
+
+func (db *Database) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.DB.QueryRow(db.Dialect.Rebind(query), args...)
+}
+
+// Insert runs an INSERT and returns the id of the new row.
+func (db *Database) Insert(query string, args ...interface{}) (int64, error) {
+	return db.Dialect.insertID(db.DB, db.Dialect.Rebind(query), args...)
+}
+
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.Exec(tx.dialect.Rebind(query), args...)
+}
+
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.Query(tx.dialect.Rebind(query), args...)
+}
+
+func (tx *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRow(tx.dialect.Rebind(query), args...)
+}
+
+// Insert runs an INSERT and returns the id of the new row.
+func (tx *Tx) Insert(query string, args ...interface{}) (int64, error) {
+	return tx.dialect.insertID(tx.Tx, tx.dialect.Rebind(query), args...)
+}
+
+// ForUpdate returns the clause that locks the rows a SELECT reads until the
+// transaction ends, or nothing where the dialect does not need it.
+func (tx *Tx) ForUpdate() string {
+	return tx.dialect.ForUpdate()
+}
+
+// IsUniqueViolation reports whether err was caused by a UNIQUE or PRIMARY
+// KEY constraint, whichever database it came from.
+func IsUniqueViolation(err error) bool {
+	return sqliteDialect{}.IsUniqueViolation(err) || postgresDialect{}.IsUniqueViolation(err)
+}
+
+// Connect opens a database with the given driver ("sqlite", the default, or
+// "postgres") without touching its schema. For SQLite, url is the path of
+// the database file and defaults to ./legiskuy.db.
+func Connect(driver, url string) (*Database, error) {
+	dialect, err := NewDialect(driver)
+	if err != nil {
+		return nil, err
 	}
-	if _, err := DB.Exec(usersTable); err != nil {
-		log.Fatal("Gagal membuat tabel users:", err)
+
+	dsn := url
+	switch dialect.Name() {
+	case "sqlite":
+		if dsn == "" {
+			dsn = "./legiskuy.db"
+		}
+		// Writers take the lock when the transaction begins and wait for
+		// each other instead of failing with "database is locked".
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "_busy_timeout=5000&_txlock=immediate"
+	case "postgres":
+		if dsn == "" {
+			return nil, errors.New("a database URL is required for PostgreSQL")
+		}
 	}
-	if _, err := DB.Exec(votesTable); err != nil {
-		log.Fatal("Gagal membuat tabel votes:", err)
+
+	db, err := sql.Open(dialect.DriverName(), dsn)
+	if err != nil {
+		return nil, err
 	}
-	if _, err := DB.Exec(settingsTable); err != nil {
-		log.Fatal("Gagal membuat tabel settings:", err)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
 	}
+	return New(db, dialect), nil
+}
 
-	log.Println("Tabel berhasil dibuat atau sudah ada.")
+// NewBallotID returns a random identifier for a ballot row. It is random
+// rather than sequential so that it says nothing about when the ballot was
+// cast relative to the others.
+func NewBallotID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
 }
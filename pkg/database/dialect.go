@@ -0,0 +1,108 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Dialect covers what differs between the supported databases. Queries are
+// written once with ? placeholders and rebound for the database in use.
+type Dialect interface {
+	// Name is the dialect's name in DB_DRIVER and the directory of its
+	// migrations.
+	Name() string
+	// DriverName is the database/sql driver the dialect is used with.
+	DriverName() string
+	Rebind(query string) string
+	// ForUpdate is appended to a SELECT to lock the rows it reads until the
+	// transaction ends, where the database locks rows at all.
+	ForUpdate() string
+	IsUniqueViolation(err error) bool
+
+	insertID(q querier, query string, args ...interface{}) (int64, error)
+}
+
+// NewDialect returns the dialect with the given name.
+func NewDialect(name string) (Dialect, error) {
+	switch name {
+	case "sqlite", "sqlite3", "":
+		return sqliteDialect{}, nil
+	case "postgres", "postgresql":
+		return postgresDialect{}, nil
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", name)
+}
+
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// sqliteDialect serializes writers itself: transactions take the write lock
+// when they begin, so there is nothing to lock row by row.
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string               { return "sqlite" }
+func (sqliteDialect) DriverName() string         { return "sqlite3" }
+func (sqliteDialect) Rebind(query string) string { return query }
+func (sqliteDialect) ForUpdate() string          { return "" }
+
+func (sqliteDialect) IsUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey)
+}
+
+func (sqliteDialect) insertID(q querier, query string, args ...interface{}) (int64, error) {
+	result, err := q.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string       { return "postgres" }
+func (postgresDialect) DriverName() string { return "postgres" }
+func (postgresDialect) ForUpdate() string  { return " FOR UPDATE" }
+
+// Rebind numbers the ? placeholders as $1, $2, ... leaving those inside
+// string literals alone.
+func (postgresDialect) Rebind(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == '?' && !quoted:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (postgresDialect) IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// insertID relies on every table inserted into this way having an id column,
+// as PostgreSQL has no last insert ID.
+func (postgresDialect) insertID(q querier, query string, args ...interface{}) (int64, error) {
+	var id int64
+	err := q.QueryRow(query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
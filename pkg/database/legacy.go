@@ -0,0 +1,508 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"legiskuy-backend/pkg/ledger"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// upgradeLegacyData brings a database created before versioned migrations
+// existed up to the schema of migration 0001. Every step checks what is
+// already there, so on a new database it changes nothing.
+func upgradeLegacyData(tx *Tx) error {
+	steps := []func(tx *Tx) error{
+		linkVoterAccounts,
+		migrateElections,
+		migrateElectionStatus,
+		func(tx *Tx) error {
+			if _, err := addColumn(tx, "candidates", "district_id", "INTEGER REFERENCES districts(id)"); err != nil {
+				return err
+			}
+			_, err := addColumn(tx, "voters", "district_id", "INTEGER REFERENCES districts(id)")
+			return err
+		},
+		migrateParties,
+		func(tx *Tx) error {
+			_, err := addColumn(tx, "elections", "party_threshold", "REAL NOT NULL DEFAULT 0")
+			return err
+		},
+		separateBallots,
+		func(tx *Tx) error {
+			if _, err := addColumn(tx, "elections", "ledger_root", "TEXT"); err != nil {
+				return err
+			}
+			_, err := addColumn(tx, "elections", "ledger_size", "INTEGER")
+			return err
+		},
+		buildLedgers,
+	}
+	for _, step := range steps {
+		if err := step(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func linkVoterAccounts(tx *Tx) error {
+	if _, err := addColumn(tx, "voters", "user_id", "INTEGER REFERENCES users(id)"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_voters_user_id ON voters(user_id)`); err != nil {
+		return fmt.Errorf("gagal membuat index voters.user_id: %w", err)
+	}
+
+	// Older voters were only related to their account by name, so link them
+	// only when the name is unambiguous on both sides.
+	linkVoters := `
+	UPDATE voters SET user_id = (
+		SELECT u.id FROM users u WHERE u.role = 'pemilih' AND u.name = voters.name
+	)
+	WHERE user_id IS NULL
+		AND (SELECT COUNT(*) FROM users u WHERE u.role = 'pemilih' AND u.name = voters.name) = 1
+		AND (SELECT COUNT(*) FROM voters v WHERE v.name = voters.name) = 1
+		AND NOT EXISTS (
+			SELECT 1 FROM voters v
+			JOIN users u ON u.id = v.user_id
+			WHERE u.role = 'pemilih' AND u.name = voters.name
+		);`
+
+	result, err := tx.Exec(linkVoters)
+	if err != nil {
+		return fmt.Errorf("gagal menautkan pemilih ke akun pengguna: %w", err)
+	}
+	if linked, _ := result.RowsAffected(); linked > 0 {
+		log.Printf("%d pemilih berhasil ditautkan ke akun pengguna.", linked)
+	}
+
+	var unlinked int
+	unlinkedQuery := `
+	SELECT COUNT(*) FROM users u
+	WHERE u.role = 'pemilih' AND NOT EXISTS (SELECT 1 FROM voters v WHERE v.user_id = u.id)`
+	if err := tx.QueryRow(unlinkedQuery).Scan(&unlinked); err != nil {
+		return fmt.Errorf("gagal memeriksa akun pemilih: %w", err)
+	}
+	if unlinked > 0 {
+		log.Printf("Peringatan: %d akun pemilih belum tertaut ke data pemilih.", unlinked)
+	}
+	return nil
+}
+
+// migrateElections moves data from the single-election layout, where the
+// schedule and threshold lived in the settings table, into a default election.
+func migrateElections(tx *Tx) error {
+	if _, err := addColumn(tx, "candidates", "election_id", "INTEGER REFERENCES elections(id)"); err != nil {
+		return err
+	}
+
+	legacyVotes, err := tableExists(tx, "votes")
+	if err != nil {
+		return err
+	}
+	if legacyVotes {
+		if _, err := addColumn(tx, "votes", "election_id", "INTEGER REFERENCES elections(id)"); err != nil {
+			return err
+		}
+	}
+
+	var elections, orphans int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM elections`).Scan(&elections); err != nil {
+		return fmt.Errorf("gagal memeriksa tabel elections: %w", err)
+	}
+	orphansQuery := `
+	SELECT (SELECT COUNT(*) FROM candidates WHERE election_id IS NULL)
+		+ (SELECT COUNT(*) FROM settings WHERE key IN ('start_time', 'end_time', 'threshold'))`
+	if legacyVotes {
+		orphansQuery += ` + (SELECT COUNT(*) FROM votes WHERE election_id IS NULL)`
+	}
+	if err := tx.QueryRow(orphansQuery).Scan(&orphans); err != nil {
+		return fmt.Errorf("gagal memeriksa data pemilu lama: %w", err)
+	}
+	if elections > 0 || orphans == 0 {
+		return nil
+	}
+
+	insertElection := `
+	INSERT INTO elections (name, start_time, end_time, threshold)
+	VALUES (
+		'Pemilu Legislatif',
+		(SELECT value FROM settings WHERE key = 'start_time'),
+		(SELECT value FROM settings WHERE key = 'end_time'),
+		COALESCE((SELECT CAST(value AS INTEGER) FROM settings WHERE key = 'threshold'), 0)
+	)`
+	result, err := tx.Exec(insertElection)
+	if err != nil {
+		return fmt.Errorf("gagal membuat pemilu bawaan: %w", err)
+	}
+	electionID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("gagal membuat pemilu bawaan: %w", err)
+	}
+
+	steps := []string{
+		`UPDATE candidates SET election_id = ? WHERE election_id IS NULL`,
+	}
+	if legacyVotes {
+		steps = append(steps, `UPDATE votes SET election_id = ? WHERE election_id IS NULL`)
+	}
+	for _, step := range steps {
+		if _, err := tx.Exec(step, electionID); err != nil {
+			return fmt.Errorf("gagal memindahkan data ke pemilu bawaan: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM settings WHERE key IN ('start_time', 'end_time', 'threshold')`); err != nil {
+		return fmt.Errorf("gagal membersihkan pengaturan pemilu lama: %w", err)
+	}
+	log.Printf("Data pemilu lama dipindahkan ke pemilu #%d.", electionID)
+
+	return deriveElectionStatus(tx, `WHERE id = ?`, electionID)
+}
+
+func migrateElectionStatus(tx *Tx) error {
+	added, err := addColumn(tx, "elections", "status", "TEXT NOT NULL DEFAULT 'draft'")
+	if err != nil || !added {
+		return err
+	}
+	return deriveElectionStatus(tx, "")
+}
+
+// deriveElectionStatus sets a lifecycle status for elections created before
+// statuses existed, based on their schedule and whether votes were cast.
+func deriveElectionStatus(tx *Tx, where string, args ...interface{}) error {
+	legacyVotes, err := tableExists(tx, "votes")
+	if err != nil {
+		return err
+	}
+	voted := "participations"
+	if legacyVotes {
+		voted = "votes"
+	}
+	query := `SELECT id, start_time, end_time, EXISTS (SELECT 1 FROM ` + voted + ` v WHERE v.election_id = elections.id) FROM elections ` + where
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("gagal membaca data pemilu: %w", err)
+	}
+
+	statuses := map[int64]string{}
+	now := time.Now().UTC()
+	for rows.Next() {
+		var id int64
+		var startTime, endTime sql.NullTime
+		var hasVotes bool
+		if err := rows.Scan(&id, &startTime, &endTime, &hasVotes); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal membaca data pemilu: %w", err)
+		}
+
+		switch {
+		case startTime.Valid && endTime.Valid && now.Before(startTime.Time):
+			statuses[id] = "scheduled"
+		case startTime.Valid && endTime.Valid && now.After(endTime.Time):
+			statuses[id] = "closed"
+		case startTime.Valid && endTime.Valid, hasVotes:
+			statuses[id] = "open"
+		default:
+			statuses[id] = "draft"
+		}
+	}
+	rows.Close()
+
+	for id, status := range statuses {
+		if _, err := tx.Exec(`UPDATE elections SET status = ? WHERE id = ?`, status, id); err != nil {
+			return fmt.Errorf("gagal memperbarui status pemilu: %w", err)
+		}
+		log.Printf("Status pemilu #%d diatur menjadi %s.", id, status)
+	}
+	return nil
+}
+
+// migrateParties replaces the free-text candidates.party column with a
+// reference to the parties table. Spellings that only differ in case,
+// spacing or punctuation ("PDI-P", "pdip") become a single party, named after
+// the first spelling found; ballot numbers follow the order of first use.
+func migrateParties(tx *Tx) error {
+	if _, err := addColumn(tx, "candidates", "party_id", "INTEGER REFERENCES parties(id)"); err != nil {
+		return err
+	}
+
+	legacy, err := columnExists(tx, "candidates", "party")
+	if err != nil || !legacy {
+		return err
+	}
+
+	partyIDs := map[string]int64{}
+	rows, err := tx.Query(`SELECT id, name, abbreviation FROM parties`)
+	if err != nil {
+		return fmt.Errorf("gagal membaca data partai: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		var name, abbreviation string
+		if err := rows.Scan(&id, &name, &abbreviation); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal membaca data partai: %w", err)
+		}
+		partyIDs[partyKey(name)] = id
+		partyIDs[partyKey(abbreviation)] = id
+	}
+	rows.Close()
+
+	type legacyCandidate struct {
+		id    int64
+		party string
+	}
+	var candidates []legacyCandidate
+	rows, err = tx.Query(`SELECT id, party FROM candidates WHERE party_id IS NULL ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("gagal membaca partai calon: %w", err)
+	}
+	for rows.Next() {
+		var c legacyCandidate
+		if err := rows.Scan(&c.id, &c.party); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal membaca partai calon: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+
+	var ballotNumber int64
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(ballot_number), 0) FROM parties`).Scan(&ballotNumber); err != nil {
+		return fmt.Errorf("gagal membaca nomor urut partai: %w", err)
+	}
+
+	created := 0
+	for _, c := range candidates {
+		name := strings.TrimSpace(c.party)
+		key := partyKey(name)
+		id, ok := partyIDs[key]
+		if !ok {
+			ballotNumber++
+			result, err := tx.Exec(`INSERT INTO parties (name, abbreviation, ballot_number) VALUES (?, ?, ?)`, name, name, ballotNumber)
+			if err != nil {
+				return fmt.Errorf("gagal membuat partai %q: %w", name, err)
+			}
+			if id, err = result.LastInsertId(); err != nil {
+				return fmt.Errorf("gagal membuat partai %q: %w", name, err)
+			}
+			partyIDs[key] = id
+			created++
+		}
+		if _, err := tx.Exec(`UPDATE candidates SET party_id = ? WHERE id = ?`, id, c.id); err != nil {
+			return fmt.Errorf("gagal menautkan calon ke partai: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`ALTER TABLE candidates DROP COLUMN party`); err != nil {
+		return fmt.Errorf("gagal menghapus kolom candidates.party: %w", err)
+	}
+	log.Printf("%d calon ditautkan ke %d partai baru.", len(candidates), created)
+	return nil
+}
+
+// partyKey reduces a party name to upper-case letters and digits so that
+// different spellings of the same party compare equal.
+func partyKey(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// separateBallots splits the legacy votes table, which stored every voter
+// next to their choice, into participations and ballots and then drops it.
+// The migration is marked for a VACUUM so the old rows do not linger in free
+// pages.
+func separateBallots(tx *Tx) error {
+	legacyVotes, err := tableExists(tx, "votes")
+	if err != nil || !legacyVotes {
+		return err
+	}
+
+	var duplicated int
+	duplicatedQuery := `
+	SELECT COUNT(*) FROM (
+		SELECT election_id, voter_id FROM votes GROUP BY election_id, voter_id HAVING COUNT(*) > 1
+	)`
+	if err := tx.QueryRow(duplicatedQuery).Scan(&duplicated); err != nil {
+		return fmt.Errorf("gagal memeriksa data suara: %w", err)
+	}
+	if duplicated > 0 {
+		return fmt.Errorf("ditemukan %d pemilih dengan lebih dari satu suara, periksa tabel votes sebelum melanjutkan", duplicated)
+	}
+
+	if _, err := tx.Exec(`INSERT INTO participations (election_id, voter_id) SELECT election_id, voter_id FROM votes`); err != nil {
+		return fmt.Errorf("gagal memindahkan data partisipasi pemilih: %w", err)
+	}
+
+	type legacyBallot struct {
+		electionID  int64
+		candidateID int64
+		castDate    string
+	}
+	var ballots []legacyBallot
+	rows, err := tx.Query(`SELECT election_id, candidate_id, COALESCE(date(created_at), date('now')) FROM votes`)
+	if err != nil {
+		return fmt.Errorf("gagal membaca data suara: %w", err)
+	}
+	for rows.Next() {
+		var b legacyBallot
+		if err := rows.Scan(&b.electionID, &b.candidateID, &b.castDate); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal membaca data suara: %w", err)
+		}
+		ballots = append(ballots, b)
+	}
+	rows.Close()
+
+	for _, b := range ballots {
+		query := `INSERT INTO ballots (id, election_id, candidate_id, cast_date) VALUES (?, ?, ?, ?)`
+		if _, err := tx.Exec(query, NewBallotID(), b.electionID, b.candidateID, b.castDate); err != nil {
+			return fmt.Errorf("gagal memindahkan surat suara: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DROP TABLE votes`); err != nil {
+		return fmt.Errorf("gagal menghapus tabel votes: %w", err)
+	}
+	log.Printf("%d suara dipisahkan menjadi data partisipasi dan surat suara.", len(ballots))
+	return nil
+}
+
+// buildLedgers appends ballots cast before the ledger existed, in ballot ID
+// order since their real order is unknown, and publishes the Merkle root of
+// elections that are already closed.
+func buildLedgers(tx *Tx) error {
+	missingQuery := `
+	SELECT b.id, b.election_id, b.candidate_id, b.cast_date FROM ballots b
+	WHERE NOT EXISTS (SELECT 1 FROM ledger_entries l WHERE l.ballot_id = b.id)
+	ORDER BY b.election_id, b.id`
+	rows, err := tx.Query(missingQuery)
+	if err != nil {
+		return fmt.Errorf("gagal membaca surat suara: %w", err)
+	}
+	var missing []ledger.Entry
+	for rows.Next() {
+		var e ledger.Entry
+		var castDate time.Time
+		if err := rows.Scan(&e.BallotID, &e.ElectionID, &e.CandidateID, &castDate); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal membaca surat suara: %w", err)
+		}
+		e.CastDate = castDate.Format("2006-01-02")
+		missing = append(missing, e)
+	}
+	rows.Close()
+
+	for _, m := range missing {
+		seq, prevHash := 0, ledger.GenesisHash
+		headQuery := `SELECT seq, hash FROM ledger_entries WHERE election_id = ? ORDER BY seq DESC LIMIT 1`
+		if err := tx.QueryRow(headQuery, m.ElectionID).Scan(&seq, &prevHash); err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("gagal membaca ledger: %w", err)
+		}
+		e := ledger.NewEntry(m.ElectionID, seq+1, m.BallotID, m.CandidateID, m.CastDate, prevHash)
+		insert := `INSERT INTO ledger_entries (election_id, seq, ballot_id, candidate_id, cast_date, prev_hash, hash) VALUES (?, ?, ?, ?, ?, ?, ?)`
+		if _, err := tx.Exec(insert, e.ElectionID, e.Seq, e.BallotID, e.CandidateID, e.CastDate, e.PrevHash, e.Hash); err != nil {
+			return fmt.Errorf("gagal menambahkan entri ledger: %w", err)
+		}
+	}
+	if len(missing) > 0 {
+		log.Printf("%d surat suara lama ditambahkan ke ledger.", len(missing))
+	}
+
+	var closed []int64
+	rows, err = tx.Query(`SELECT id FROM elections WHERE status IN ('closed', 'certified') AND ledger_root IS NULL`)
+	if err != nil {
+		return fmt.Errorf("gagal membaca data pemilu: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal membaca data pemilu: %w", err)
+		}
+		closed = append(closed, id)
+	}
+	rows.Close()
+
+	for _, id := range closed {
+		var hashes []string
+		rows, err := tx.Query(`SELECT hash FROM ledger_entries WHERE election_id = ? ORDER BY seq`, id)
+		if err != nil {
+			return fmt.Errorf("gagal membaca ledger: %w", err)
+		}
+		for rows.Next() {
+			var h string
+			if err := rows.Scan(&h); err != nil {
+				rows.Close()
+				return fmt.Errorf("gagal membaca ledger: %w", err)
+			}
+			hashes = append(hashes, h)
+		}
+		rows.Close()
+
+		root, err := ledger.MerkleRoot(hashes)
+		if err != nil {
+			return fmt.Errorf("gagal menghitung Merkle root: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE elections SET ledger_root = ?, ledger_size = ? WHERE id = ?`, root, len(hashes), id); err != nil {
+			return fmt.Errorf("gagal menyimpan Merkle root: %w", err)
+		}
+		log.Printf("Merkle root pemilu #%d diterbitkan.", id)
+	}
+	return nil
+}
+
+// addColumn adds the column when it is missing and reports whether it did.
+func addColumn(tx *Tx, table, column, definition string) (bool, error) {
+	exists, err := columnExists(tx, table, column)
+	if err != nil {
+		return false, fmt.Errorf("gagal membaca struktur tabel %s: %w", table, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	query := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN "%s" %s`, table, column, definition)
+	if _, err := tx.Exec(query); err != nil {
+		return false, fmt.Errorf("gagal menambahkan kolom %s.%s: %w", table, column, err)
+	}
+	log.Printf("Kolom %s.%s berhasil ditambahkan.", table, column)
+	return true, nil
+}
+
+func tableExists(tx *Tx, table string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?)`
+	if err := tx.QueryRow(query, table).Scan(&exists); err != nil {
+		return false, fmt.Errorf("gagal memeriksa tabel %s: %w", table, err)
+	}
+	return exists, nil
+}
+
+func columnExists(tx *Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
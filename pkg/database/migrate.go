@@ -0,0 +1,361 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrations live in migrations/<dialect>/ as NNNN_name.up.sql and
+// NNNN_name.down.sql and are compiled into the binary. Every dialect has the
+// same migrations under the same version numbers. Changes that need more than
+// SQL, such as the upgrade of databases from older releases, are registered
+// in goMigrations under their own version number instead.
+//
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+var goMigrations = map[string][]Migration{
+	// Only SQLite databases predate versioned migrations.
+	"sqlite": {
+		{
+			Version: 2,
+			Name:    "upgrade_legacy_data",
+			up:      upgradeLegacyData,
+			// The upgraded data already fits the schema of migration 0001,
+			// so there is nothing to undo.
+			down:   func(tx *Tx) error { return nil },
+			vacuum: true,
+		},
+	},
+}
+
+// lockTimeout is how long a migration run waits for another process that
+// holds the schema lock.
+var lockTimeout = time.Minute
+
+type Migration struct {
+	Version int
+	Name    string
+
+	up   func(tx *Tx) error
+	down func(tx *Tx) error
+	// vacuum rewrites the database file after the migration so that rows it
+	// removed do not linger in free pages.
+	vacuum bool
+}
+
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// ErrSchemaLocked is returned when another process kept the schema lock for
+// longer than lockTimeout.
+var ErrSchemaLocked = errors.New("schema is locked by another migration")
+
+// Migrate applies up to steps pending migrations in version order, or all of
+// them when steps is zero, and returns the ones it applied.
+func Migrate(db *Database, steps int) ([]Migration, error) {
+	migrations, err := loadMigrations(db.Dialect.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = withSchemaLock(db, func() error {
+		done, err := appliedVersions(db)
+		if err != nil {
+			return err
+		}
+		vacuum := false
+		for _, m := range migrations {
+			if steps > 0 && len(applied) == steps {
+				break
+			}
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			if err := runMigration(db, m.up, `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`, m.Version, m.Name, time.Now().UTC()); err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+			}
+			log.Printf("Migrasi %04d_%s berhasil diterapkan.", m.Version, m.Name)
+			applied = append(applied, m)
+			vacuum = vacuum || m.vacuum
+		}
+		if vacuum {
+			if _, err := db.Exec(`VACUUM`); err != nil {
+				return fmt.Errorf("gagal membersihkan berkas database: %w", err)
+			}
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Rollback reverts the last steps applied migrations, newest first, and
+// returns the ones it reverted.
+func Rollback(db *Database, steps int) ([]Migration, error) {
+	migrations, err := loadMigrations(db.Dialect.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	err = withSchemaLock(db, func() error {
+		done, err := appliedVersions(db)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			if m.down == nil {
+				return fmt.Errorf("migration %04d_%s cannot be rolled back", m.Version, m.Name)
+			}
+			if err := runMigration(db, m.down, `DELETE FROM schema_migrations WHERE version = ?`, m.Version); err != nil {
+				return fmt.Errorf("rollback of %04d_%s failed: %w", m.Version, m.Name, err)
+			}
+			log.Printf("Migrasi %04d_%s berhasil dibatalkan.", m.Version, m.Name)
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// MigrationStatuses lists every known migration and when it was applied, nil
+// for those still pending.
+func MigrationStatuses(db *Database) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(db.Dialect.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := createMigrationTables(db); err != nil {
+		return nil, err
+	}
+	done, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if appliedAt, ok := done[m.Version]; ok {
+			t := appliedAt
+			status.AppliedAt = &t
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Unlock releases the schema lock left behind by a migration run that did
+// not finish, for example because its process was killed.
+func Unlock(db *Database) error {
+	if err := createMigrationTables(db); err != nil {
+		return err
+	}
+	_, err := db.Exec(`DELETE FROM schema_lock`)
+	return err
+}
+
+// runMigration runs one direction of a migration together with the change to
+// schema_migrations that records it, so that both happen or neither does.
+func runMigration(db *Database, apply func(tx *Tx) error, record string, args ...interface{}) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := apply(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// withSchemaLock runs fn while holding the row in schema_lock, so that two
+// processes started at the same time do not migrate the same database.
+func withSchemaLock(db *Database, fn func() error) error {
+	if err := createMigrationTables(db); err != nil {
+		return err
+	}
+
+	owner := lockOwner()
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		result, err := db.Exec(`INSERT INTO schema_lock (id, owner, locked_at) VALUES (1, ?, ?) ON CONFLICT DO NOTHING`, owner, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			var holder string
+			var since time.Time
+			if err := db.QueryRow(`SELECT owner, locked_at FROM schema_lock WHERE id = 1`).Scan(&holder, &since); err != nil && err != sql.ErrNoRows {
+				return err
+			}
+			return fmt.Errorf("%w (%s since %s)", ErrSchemaLocked, holder, since.Format(time.RFC3339))
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	defer func() {
+		if _, err := db.Exec(`DELETE FROM schema_lock WHERE id = 1 AND owner = ?`, owner); err != nil {
+			log.Println("Gagal melepas kunci migrasi:", err)
+		}
+	}()
+
+	return fn()
+}
+
+func lockOwner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+func createMigrationTables(db *Database) error {
+	schemaMigrationsTable := `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		"version" INTEGER NOT NULL PRIMARY KEY,
+		"name" TEXT NOT NULL,
+		"applied_at" TIMESTAMP NOT NULL
+	);`
+
+	schemaLockTable := `
+	CREATE TABLE IF NOT EXISTS schema_lock (
+		"id" INTEGER NOT NULL PRIMARY KEY CHECK (id = 1),
+		"owner" TEXT NOT NULL,
+		"locked_at" TIMESTAMP NOT NULL
+	);`
+
+	if _, err := db.Exec(schemaMigrationsTable); err != nil {
+		return fmt.Errorf("gagal membuat tabel schema_migrations: %w", err)
+	}
+	if _, err := db.Exec(schemaLockTable); err != nil {
+		return fmt.Errorf("gagal membuat tabel schema_lock: %w", err)
+	}
+	return nil
+}
+
+func appliedVersions(db *Database) (map[int]time.Time, error) {
+	rows, err := db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+	return done, rows.Err()
+}
+
+// loadMigrations reads the embedded SQL migrations, adds the Go ones and
+// returns them in version order. Every version must have an up migration and
+// may only be defined once.
+func loadMigrations(dialect string) ([]Migration, error) {
+	byVersion := make(map[int]*Migration)
+	for i := range goMigrations[dialect] {
+		m := goMigrations[dialect][i]
+		byVersion[m.Version] = &m
+	}
+
+	dir := "migrations/" + dialect
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		version, name, direction, err := parseMigrationName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		data, err := migrationFiles.ReadFile(dir + "/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %04d is defined as both %s and %s", version, m.Name, name)
+		}
+
+		apply := execSQL(string(data))
+		switch direction {
+		case "up":
+			if m.up != nil {
+				return nil, fmt.Errorf("migration %04d_%s has more than one up migration", version, name)
+			}
+			m.up = apply
+		case "down":
+			if m.down != nil {
+				return nil, fmt.Errorf("migration %04d_%s has more than one down migration", version, name)
+			}
+			m.down = apply
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == nil {
+			return nil, fmt.Errorf("migration %04d_%s has no up migration", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// parseMigrationName splits a file name such as 0003_add_users.up.sql into
+// its version, name and direction.
+func parseMigrationName(file string) (int, string, string, error) {
+	base := strings.TrimSuffix(file, ".sql")
+	direction := base[strings.LastIndex(base, ".")+1:]
+	if direction != "up" && direction != "down" {
+		return 0, "", "", fmt.Errorf("migration %s must end in .up.sql or .down.sql", file)
+	}
+	base = strings.TrimSuffix(base, "."+direction)
+
+	number, name, found := strings.Cut(base, "_")
+	version, err := strconv.Atoi(number)
+	if !found || err != nil || version <= 0 || name == "" {
+		return 0, "", "", fmt.Errorf("migration %s must be named NNNN_name.%s.sql", file, direction)
+	}
+	return version, name, direction, nil
+}
+
+func execSQL(statements string) func(tx *Tx) error {
+	return func(tx *Tx) error {
+		_, err := tx.Exec(statements)
+		return err
+	}
+}
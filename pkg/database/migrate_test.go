@@ -0,0 +1,112 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func connect(t *testing.T) *Database {
+	t.Helper()
+	db, err := Connect("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrateWaitsForSchemaLock(t *testing.T) {
+	db := connect(t)
+	defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+	lockTimeout = 0
+
+	// A run that was killed leaves its lock behind.
+	if err := createMigrationTables(db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_lock (id, owner, locked_at) VALUES (1, 'other:1', ?)`, time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(db, 0); !errors.Is(err, ErrSchemaLocked) {
+		t.Fatalf("got %v, want ErrSchemaLocked", err)
+	}
+	if _, err := Rollback(db, 1); !errors.Is(err, ErrSchemaLocked) {
+		t.Fatalf("got %v from the rollback, want ErrSchemaLocked", err)
+	}
+
+	if err := Unlock(db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	// A finished run releases its own lock.
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_lock`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("got %d lock rows after the run, want none", n)
+	}
+}
+
+func TestMigrateRoundTrip(t *testing.T) {
+	db := connect(t)
+	migrations, err := loadMigrations(db.Dialect.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := Migrate(db, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(migrations))
+	}
+	if applied, err := Migrate(db, 0); err != nil || len(applied) != 0 {
+		t.Fatalf("second run applied %d migrations (%v), want none", len(applied), err)
+	}
+
+	reverted, err := Rollback(db, len(migrations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != len(migrations) || reverted[0].Version != migrations[len(migrations)-1].Version {
+		t.Fatalf("reverted %d migrations starting at %d, want all starting at the newest", len(reverted), reverted[0].Version)
+	}
+
+	// Every down migration must leave a schema the up migrations apply to.
+	if _, err := Migrate(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := MigrationStatuses(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("migration %04d_%s is pending", s.Version, s.Name)
+		}
+	}
+}
+
+func TestMigrateSteps(t *testing.T) {
+	db := connect(t)
+	applied, err := Migrate(db, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 2 || applied[0].Version != 1 || applied[1].Version != 2 {
+		t.Fatalf("got %+v, want migrations 1 and 2", applied)
+	}
+	reverted, err := Rollback(db, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != 1 || reverted[0].Version != 2 {
+		t.Fatalf("got %+v, want migration 2", reverted)
+	}
+}
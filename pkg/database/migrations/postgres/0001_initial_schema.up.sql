@@ -0,0 +1,128 @@
+-- PostgreSQL version of the sqlite schema of the same migration. Tables are
+-- created in dependency order because PostgreSQL checks foreign keys.
+
+CREATE TABLE IF NOT EXISTS users (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"name" TEXT NOT NULL,
+	"username" TEXT NOT NULL UNIQUE,
+	"password" TEXT NOT NULL,
+	"role" TEXT NOT NULL,
+	"has_voted" BOOLEAN DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS districts (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"name" TEXT NOT NULL UNIQUE,
+	"seats" INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS parties (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"name" TEXT NOT NULL UNIQUE,
+	"abbreviation" TEXT NOT NULL UNIQUE,
+	"ballot_number" INTEGER NOT NULL UNIQUE,
+	"logo_url" TEXT NOT NULL DEFAULT '',
+	"color" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS elections (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"name" TEXT NOT NULL,
+	"description" TEXT NOT NULL DEFAULT '',
+	"start_time" TIMESTAMPTZ,
+	"end_time" TIMESTAMPTZ,
+	"threshold" INTEGER NOT NULL DEFAULT 0,
+	"party_threshold" DOUBLE PRECISION NOT NULL DEFAULT 0,
+	"ledger_root" TEXT,
+	"ledger_size" INTEGER,
+	"ballot_title" TEXT NOT NULL DEFAULT '',
+	"ballot_order" TEXT NOT NULL DEFAULT 'id',
+	"created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+	"status" TEXT NOT NULL DEFAULT 'draft'
+);
+
+CREATE TABLE IF NOT EXISTS election_transitions (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"election_id" INTEGER NOT NULL REFERENCES elections(id),
+	"from_status" TEXT NOT NULL,
+	"to_status" TEXT NOT NULL,
+	"user_id" INTEGER REFERENCES users(id),
+	"note" TEXT NOT NULL DEFAULT '',
+	"created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS candidates (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"name" TEXT NOT NULL,
+	"votes" INTEGER DEFAULT 0,
+	"election_id" INTEGER REFERENCES elections(id),
+	"district_id" INTEGER REFERENCES districts(id),
+	"party_id" INTEGER REFERENCES parties(id)
+);
+
+CREATE TABLE IF NOT EXISTS voters (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"name" TEXT NOT NULL,
+	"has_voted" BOOLEAN DEFAULT FALSE,
+	"user_id" INTEGER UNIQUE REFERENCES users(id),
+	"district_id" INTEGER REFERENCES districts(id)
+);
+
+-- Who voted and what was chosen are kept in two tables with nothing to join
+-- them on: participations carry no time at all and ballots only the day they
+-- were cast, under a random ID.
+CREATE TABLE IF NOT EXISTS participations (
+	"election_id" INTEGER NOT NULL REFERENCES elections(id),
+	"voter_id" INTEGER NOT NULL REFERENCES voters(id),
+	PRIMARY KEY(election_id, voter_id)
+);
+
+CREATE TABLE IF NOT EXISTS ballots (
+	"id" TEXT NOT NULL PRIMARY KEY,
+	"election_id" INTEGER NOT NULL REFERENCES elections(id),
+	"candidate_id" INTEGER NOT NULL REFERENCES candidates(id),
+	"cast_date" DATE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+	"election_id" INTEGER NOT NULL REFERENCES elections(id),
+	"seq" INTEGER NOT NULL,
+	"ballot_id" TEXT NOT NULL UNIQUE REFERENCES ballots(id),
+	"candidate_id" INTEGER NOT NULL,
+	"cast_date" TEXT NOT NULL,
+	"prev_hash" TEXT NOT NULL,
+	"hash" TEXT NOT NULL,
+	PRIMARY KEY(election_id, seq)
+);
+
+CREATE TABLE IF NOT EXISTS receipts (
+	"receipt_hash" TEXT NOT NULL PRIMARY KEY,
+	"election_id" INTEGER NOT NULL REFERENCES elections(id),
+	"ballot_id" TEXT NOT NULL UNIQUE REFERENCES ballots(id)
+);
+
+CREATE TABLE IF NOT EXISTS recounts (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	"election_id" INTEGER NOT NULL REFERENCES elections(id),
+	"user_id" INTEGER REFERENCES users(id),
+	"repair" BOOLEAN NOT NULL DEFAULT FALSE,
+	"note" TEXT NOT NULL DEFAULT '',
+	"participations" INTEGER NOT NULL,
+	"ballots" INTEGER NOT NULL,
+	"tallied_votes" INTEGER NOT NULL,
+	"repaired" INTEGER NOT NULL DEFAULT 0,
+	"created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS recount_discrepancies (
+	"recount_id" INTEGER NOT NULL REFERENCES recounts(id),
+	"candidate_id" INTEGER NOT NULL,
+	"tallied" INTEGER NOT NULL,
+	"ballots" INTEGER NOT NULL,
+	PRIMARY KEY(recount_id, candidate_id)
+);
+
+CREATE TABLE IF NOT EXISTS settings (
+	"key" TEXT NOT NULL PRIMARY KEY,
+	"value" TEXT
+);
@@ -0,0 +1,3 @@
+-- A disabled user can no longer log in, and the tokens they hold stop working.
+
+ALTER TABLE users ADD COLUMN "disabled" BOOLEAN NOT NULL DEFAULT FALSE;
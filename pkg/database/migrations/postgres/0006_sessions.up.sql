@@ -0,0 +1,22 @@
+-- A session starts when a user logs in and ends when they log out, when it
+-- is revoked or when one of its refresh tokens is used twice. Access tokens
+-- name their session and stop working with it. Refresh tokens are stored as
+-- SHA-256 hashes, each used once and replaced by the next.
+
+CREATE TABLE sessions (
+	"id" TEXT NOT NULL PRIMARY KEY,
+	"user_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	"created_at" TIMESTAMPTZ NOT NULL,
+	"revoked_at" TIMESTAMPTZ
+);
+
+CREATE INDEX sessions_user_id ON sessions (user_id);
+
+CREATE TABLE refresh_tokens (
+	"token_hash" TEXT NOT NULL PRIMARY KEY,
+	"session_id" TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+	"expires_at" TIMESTAMPTZ NOT NULL,
+	"used_at" TIMESTAMPTZ
+);
+
+CREATE INDEX refresh_tokens_session_id ON refresh_tokens (session_id);
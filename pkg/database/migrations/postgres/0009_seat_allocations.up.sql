@@ -0,0 +1,10 @@
+-- The seat allocation of an election as it stood when the election was
+-- certified, as the JSON served by GET /elections/{id}/results/seats. It is
+-- kept so that later changes to districts and parties leave certified
+-- results alone.
+
+CREATE TABLE seat_allocations (
+	"election_id" INTEGER NOT NULL PRIMARY KEY REFERENCES elections(id),
+	"allocation" TEXT NOT NULL,
+	"created_at" TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,8 @@
+-- The ledger of an election is built when the election closes, from its
+-- ballots in random order, rather than appended to as votes are cast. The
+-- entries of elections that are not closed yet were appended in the order
+-- the ballots were cast, so they are dropped; closing the election builds
+-- them again. Those of closed elections stay, as their Merkle root is
+-- published.
+
+DELETE FROM ledger_entries WHERE election_id IN (SELECT id FROM elections WHERE ledger_root IS NULL);
@@ -0,0 +1,9 @@
+-- The links from receipts to ballots are gone on purpose, so the column comes
+-- back empty.
+
+DELETE FROM role_permissions WHERE permission = 'ledger:read';
+DELETE FROM permissions WHERE name = 'ledger:read';
+
+ALTER TABLE elections DROP COLUMN "receipts_root";
+
+ALTER TABLE receipts ADD COLUMN "ballot_id" TEXT REFERENCES ballots(id);
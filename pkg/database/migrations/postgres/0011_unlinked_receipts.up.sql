@@ -0,0 +1,17 @@
+-- A receipt no longer points to its ballot: the ledger export maps ballots to
+-- candidates, so the link would let anyone holding a receipt find out the
+-- vote. The holder instead gets a proof that their receipt is in the Merkle
+-- tree of the receipt hashes of the election, published when it closes. The
+-- ledger entries and their proofs are only served to the roles granted
+-- ledger:read.
+
+ALTER TABLE receipts DROP COLUMN "ballot_id";
+
+ALTER TABLE elections ADD COLUMN "receipts_root" TEXT;
+
+INSERT INTO permissions (name, description) VALUES
+	('ledger:read', 'See the ballot ledger entries and their proofs');
+
+-- The auditor role may have been deleted since it was seeded.
+INSERT INTO role_permissions (role, permission)
+	SELECT name, 'ledger:read' FROM roles WHERE name IN ('super_admin', 'auditor');
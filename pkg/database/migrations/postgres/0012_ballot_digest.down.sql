@@ -0,0 +1,3 @@
+ALTER TABLE elections DROP COLUMN "ballots_count";
+
+ALTER TABLE elections DROP COLUMN "ballots_digest";
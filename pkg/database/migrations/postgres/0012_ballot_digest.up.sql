@@ -0,0 +1,11 @@
+-- The digest of the ballots of an election is kept up to date as they are
+-- stored, and checked against the stored ballots before the ledger is built
+-- at close, so that ballots changed or added behind the application's back
+-- are not sealed into the ledger. Elections that already took ballots have
+-- no digest to check against.
+
+ALTER TABLE elections ADD COLUMN "ballots_digest" TEXT DEFAULT '0000000000000000000000000000000000000000000000000000000000000000';
+
+ALTER TABLE elections ADD COLUMN "ballots_count" INTEGER NOT NULL DEFAULT 0;
+
+UPDATE elections SET ballots_digest = NULL WHERE status NOT IN ('draft', 'scheduled');
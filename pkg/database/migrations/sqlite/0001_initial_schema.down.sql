@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS settings;
+DROP TABLE IF EXISTS recount_discrepancies;
+DROP TABLE IF EXISTS recounts;
+DROP TABLE IF EXISTS receipts;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ballots;
+DROP TABLE IF EXISTS participations;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS voters;
+DROP TABLE IF EXISTS candidates;
+DROP TABLE IF EXISTS parties;
+DROP TABLE IF EXISTS districts;
+DROP TABLE IF EXISTS election_transitions;
+DROP TABLE IF EXISTS elections;
@@ -0,0 +1,148 @@
+-- Schema as of the first versioned release. Tables are created only when
+-- missing so that databases from older releases, which already have some of
+-- them, can be brought up to date by migration 0002.
+
+CREATE TABLE IF NOT EXISTS elections (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"name" TEXT NOT NULL,
+	"description" TEXT NOT NULL DEFAULT '',
+	"start_time" TIMESTAMP,
+	"end_time" TIMESTAMP,
+	"threshold" INTEGER NOT NULL DEFAULT 0,
+	"party_threshold" REAL NOT NULL DEFAULT 0,
+	"ledger_root" TEXT,
+	"ledger_size" INTEGER,
+	"ballot_title" TEXT NOT NULL DEFAULT '',
+	"ballot_order" TEXT NOT NULL DEFAULT 'id',
+	"created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	"status" TEXT NOT NULL DEFAULT 'draft'
+);
+
+CREATE TABLE IF NOT EXISTS election_transitions (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"election_id" INTEGER NOT NULL,
+	"from_status" TEXT NOT NULL,
+	"to_status" TEXT NOT NULL,
+	"user_id" INTEGER,
+	"note" TEXT NOT NULL DEFAULT '',
+	"created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY(election_id) REFERENCES elections(id),
+	FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS districts (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"name" TEXT NOT NULL UNIQUE,
+	"seats" INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS parties (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"name" TEXT NOT NULL UNIQUE,
+	"abbreviation" TEXT NOT NULL UNIQUE,
+	"ballot_number" INTEGER NOT NULL UNIQUE,
+	"logo_url" TEXT NOT NULL DEFAULT '',
+	"color" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS candidates (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"name" TEXT NOT NULL,
+	"votes" INTEGER DEFAULT 0,
+	"election_id" INTEGER,
+	"district_id" INTEGER,
+	"party_id" INTEGER,
+	FOREIGN KEY(election_id) REFERENCES elections(id),
+	FOREIGN KEY(district_id) REFERENCES districts(id),
+	FOREIGN KEY(party_id) REFERENCES parties(id)
+);
+
+CREATE TABLE IF NOT EXISTS voters (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"name" TEXT NOT NULL,
+	"has_voted" BOOLEAN DEFAULT FALSE,
+	"user_id" INTEGER UNIQUE,
+	"district_id" INTEGER,
+	FOREIGN KEY(user_id) REFERENCES users(id),
+	FOREIGN KEY(district_id) REFERENCES districts(id)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"name" TEXT NOT NULL,
+	"username" TEXT NOT NULL UNIQUE,
+	"password" TEXT NOT NULL,
+	"role" TEXT NOT NULL,
+	"has_voted" BOOLEAN DEFAULT FALSE
+);
+
+-- Who voted and what was chosen are kept in two tables with nothing to join
+-- them on: participations carry no time at all, ballots only the day they
+-- were cast, and both are WITHOUT ROWID tables keyed so that their storage
+-- order does not follow the order of insertion.
+CREATE TABLE IF NOT EXISTS participations (
+	"election_id" INTEGER NOT NULL,
+	"voter_id" INTEGER NOT NULL,
+	PRIMARY KEY(election_id, voter_id),
+	FOREIGN KEY(election_id) REFERENCES elections(id),
+	FOREIGN KEY(voter_id) REFERENCES voters(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS ballots (
+	"id" TEXT NOT NULL PRIMARY KEY,
+	"election_id" INTEGER NOT NULL,
+	"candidate_id" INTEGER NOT NULL,
+	"cast_date" DATE NOT NULL,
+	FOREIGN KEY(election_id) REFERENCES elections(id),
+	FOREIGN KEY(candidate_id) REFERENCES candidates(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+	"election_id" INTEGER NOT NULL,
+	"seq" INTEGER NOT NULL,
+	"ballot_id" TEXT NOT NULL UNIQUE,
+	"candidate_id" INTEGER NOT NULL,
+	"cast_date" TEXT NOT NULL,
+	"prev_hash" TEXT NOT NULL,
+	"hash" TEXT NOT NULL,
+	PRIMARY KEY(election_id, seq),
+	FOREIGN KEY(election_id) REFERENCES elections(id),
+	FOREIGN KEY(ballot_id) REFERENCES ballots(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS receipts (
+	"receipt_hash" TEXT NOT NULL PRIMARY KEY,
+	"election_id" INTEGER NOT NULL,
+	"ballot_id" TEXT NOT NULL UNIQUE,
+	FOREIGN KEY(election_id) REFERENCES elections(id),
+	FOREIGN KEY(ballot_id) REFERENCES ballots(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS recounts (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"election_id" INTEGER NOT NULL,
+	"user_id" INTEGER,
+	"repair" BOOLEAN NOT NULL DEFAULT FALSE,
+	"note" TEXT NOT NULL DEFAULT '',
+	"participations" INTEGER NOT NULL,
+	"ballots" INTEGER NOT NULL,
+	"tallied_votes" INTEGER NOT NULL,
+	"repaired" INTEGER NOT NULL DEFAULT 0,
+	"created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY(election_id) REFERENCES elections(id),
+	FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS recount_discrepancies (
+	"recount_id" INTEGER NOT NULL,
+	"candidate_id" INTEGER NOT NULL,
+	"tallied" INTEGER NOT NULL,
+	"ballots" INTEGER NOT NULL,
+	PRIMARY KEY(recount_id, candidate_id),
+	FOREIGN KEY(recount_id) REFERENCES recounts(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS settings (
+	"key" TEXT NOT NULL PRIMARY KEY,
+	"value" TEXT
+);
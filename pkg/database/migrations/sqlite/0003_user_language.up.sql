@@ -0,0 +1,4 @@
+-- The language a user wants the API to answer in, "" or NULL to follow the
+-- Accept-Language header of every request.
+
+ALTER TABLE users ADD COLUMN "language" TEXT;
@@ -0,0 +1,76 @@
+-- Roles and the permissions they grant. The permissions are checked by the
+-- code, so new ones are added by migrations; which roles hold them can be
+-- changed through the API.
+
+CREATE TABLE roles (
+	"name" TEXT NOT NULL PRIMARY KEY,
+	"description" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE permissions (
+	"name" TEXT NOT NULL PRIMARY KEY,
+	"description" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+	"role" TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+	"permission" TEXT NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+	PRIMARY KEY ("role", "permission")
+);
+
+INSERT INTO roles (name, description) VALUES
+	('super_admin', 'Full access, including what every role may do'),
+	('petugas', 'Election staff: manages accounts, data and elections'),
+	('district_officer', 'Manages the voters and candidates of the districts'),
+	('kpps', 'Polling station officer: helps voters cast their votes'),
+	('saksi', 'Witness: checks recounts'),
+	('auditor', 'Checks ballots and recounts'),
+	('pemilih', 'Voter');
+
+INSERT INTO permissions (name, description) VALUES
+	('users:manage', 'Create accounts, change their roles and disable them'),
+	('roles:manage', 'Change the permissions of roles'),
+	('districts:manage', 'Create, change and delete districts'),
+	('parties:manage', 'Create, change and delete parties'),
+	('candidates:manage', 'Create, change and delete candidates'),
+	('voters:manage', 'Create, change and delete voters'),
+	('elections:manage', 'Create, change, delete and move elections through their lifecycle'),
+	('elections:certify', 'Certify the results of a closed election'),
+	('recounts:run', 'Recount an election and repair its counters'),
+	('recounts:read', 'See the recounts of an election'),
+	('ballots:read_any', 'See the ballot of any district'),
+	('votes:assist', 'Cast a vote on behalf of another voter');
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('super_admin', 'users:manage'),
+	('super_admin', 'roles:manage'),
+	('super_admin', 'districts:manage'),
+	('super_admin', 'parties:manage'),
+	('super_admin', 'candidates:manage'),
+	('super_admin', 'voters:manage'),
+	('super_admin', 'elections:manage'),
+	('super_admin', 'elections:certify'),
+	('super_admin', 'recounts:run'),
+	('super_admin', 'recounts:read'),
+	('super_admin', 'ballots:read_any'),
+	('super_admin', 'votes:assist'),
+	('petugas', 'users:manage'),
+	('petugas', 'districts:manage'),
+	('petugas', 'parties:manage'),
+	('petugas', 'candidates:manage'),
+	('petugas', 'voters:manage'),
+	('petugas', 'elections:manage'),
+	('petugas', 'elections:certify'),
+	('petugas', 'recounts:run'),
+	('petugas', 'recounts:read'),
+	('petugas', 'ballots:read_any'),
+	('petugas', 'votes:assist'),
+	('district_officer', 'voters:manage'),
+	('district_officer', 'candidates:manage'),
+	('district_officer', 'ballots:read_any'),
+	('district_officer', 'recounts:read'),
+	('kpps', 'votes:assist'),
+	('kpps', 'ballots:read_any'),
+	('saksi', 'recounts:read'),
+	('auditor', 'recounts:read'),
+	('auditor', 'ballots:read_any');
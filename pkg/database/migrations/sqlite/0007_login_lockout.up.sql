@@ -0,0 +1,29 @@
+-- Failed logins are counted per username, whether or not an account has it,
+-- and per client address. Past a few failures the next try has to wait, a
+-- little longer after each one, and past many the subject is locked out
+-- until its lockout ends or a petugas lifts it. Failures are forgotten some
+-- time after the last one, and those of a username when it logs in.
+
+CREATE TABLE login_failures (
+	"scope" TEXT NOT NULL,
+	"subject" TEXT NOT NULL,
+	"failures" INTEGER NOT NULL,
+	"last_failed_at" TIMESTAMP NOT NULL,
+	"locked_until" TIMESTAMP NOT NULL,
+	PRIMARY KEY (scope, subject)
+);
+
+CREATE INDEX login_failures_locked_until ON login_failures (locked_until);
+
+-- Every login attempt and how it ended.
+CREATE TABLE login_attempts (
+	"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	"username" TEXT NOT NULL,
+	"user_id" INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	"address" TEXT NOT NULL,
+	"result" TEXT NOT NULL,
+	"attempted_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX login_attempts_username ON login_attempts (username);
+CREATE INDEX login_attempts_address ON login_attempts (address);
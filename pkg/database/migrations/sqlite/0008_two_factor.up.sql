@@ -0,0 +1,32 @@
+-- A user turns on two-factor authentication by confirming a TOTP secret
+-- (RFC 6238) with a code of their authenticator app, and gets recovery codes
+-- for when they lose it. Their logins then take two steps: the password gets
+-- a login challenge, and the challenge with a code gets the tokens. Recovery
+-- codes and challenges are stored as SHA-256 hashes.
+
+CREATE TABLE totp_secrets (
+	"user_id" INTEGER NOT NULL PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	"secret" TEXT NOT NULL,
+	"confirmed_at" TIMESTAMP,
+	-- last_step is the time step of the last code used. Codes of it and of
+	-- earlier steps are refused, so that a code works only once.
+	"last_step" INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE recovery_codes (
+	"code_hash" TEXT NOT NULL PRIMARY KEY,
+	"user_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	"used_at" TIMESTAMP
+);
+
+CREATE INDEX recovery_codes_user_id ON recovery_codes (user_id);
+
+CREATE TABLE login_challenges (
+	"token_hash" TEXT NOT NULL PRIMARY KEY,
+	"user_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	"expires_at" TIMESTAMP NOT NULL,
+	"failures" INTEGER NOT NULL DEFAULT 0,
+	"used_at" TIMESTAMP
+);
+
+CREATE INDEX login_challenges_user_id ON login_challenges (user_id);
@@ -0,0 +1,2 @@
+-- The order the dropped entries were appended in is gone on purpose, so there
+-- is nothing to restore.
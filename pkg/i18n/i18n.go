@@ -0,0 +1,194 @@
+// Package i18n translates the messages of the API. Every language has a
+// catalog in locales/<language>.json that maps keys to messages:
+//
+//	error.<code>               the message of an apperror.Error
+//	field.<field>.<code>       the message of a field error about one field
+//	field.<code>               the message of a field error, with {field}
+//	message.<key>              a success message
+//	term.<word>                a word filled into a placeholder, such as an
+//	                           election status
+//
+// Messages missing from a catalog fall back to English and then to the
+// message given in the code.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"legiskuy-backend/pkg/apperror"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	English    = "en"
+	Indonesian = "id"
+
+	// Default is used when the client states no language the API speaks.
+	Default = English
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	catalogs := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic("invalid catalog " + entry.Name() + ": " + err.Error())
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = catalog
+	}
+	return catalogs
+}
+
+// Supported reports whether there is a catalog for lang.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// T returns the message under key in lang with its placeholders filled in
+// from params, or fallback if no catalog has the key. Parameter values that
+// are terms in the catalog are translated as well.
+func T(lang, key string, params map[string]string, fallback string) string {
+	message, ok := lookup(lang, key)
+	if !ok {
+		message = fallback
+	}
+	if len(params) == 0 {
+		return message
+	}
+	translated := make(map[string]string, len(params))
+	for name, value := range params {
+		if term, ok := catalogs[lang]["term."+value]; ok {
+			value = term
+		}
+		translated[name] = value
+	}
+	return apperror.Format(message, translated)
+}
+
+func lookup(lang, key string) (string, bool) {
+	if message, ok := catalogs[lang][key]; ok {
+		return message, true
+	}
+	message, ok := catalogs[Default][key]
+	return message, ok
+}
+
+// Error returns the message and field errors of e in lang.
+func Error(lang string, e *apperror.Error) (string, []apperror.FieldError) {
+	if len(e.Fields) == 0 {
+		return T(lang, "error."+e.Code, e.Params, e.Error()), nil
+	}
+
+	fields := make([]apperror.FieldError, len(e.Fields))
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		params := map[string]string{"field": f.Field}
+		for name, value := range f.Params {
+			params[name] = value
+		}
+		message, ok := lookup(lang, "field."+f.Field+"."+f.Code)
+		if !ok {
+			message = T(lang, "field."+f.Code, params, f.Message)
+		} else {
+			message = apperror.Format(message, params)
+		}
+		fields[i] = apperror.FieldError{Field: f.Field, Code: f.Code, Message: message}
+		messages[i] = message
+	}
+	return strings.Join(messages, "; "), fields
+}
+
+const localsKey = "language"
+
+// New picks the language of every request from its Accept-Language header.
+// Handlers further down may switch it with SetLanguage.
+func New() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		SetLanguage(c, Requested(c))
+		return c.Next()
+	}
+}
+
+// Requested returns the language the Accept-Language header of the request
+// asks for, or Default.
+func Requested(c *fiber.Ctx) string {
+	if lang := FromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage)); lang != "" {
+		return lang
+	}
+	return Default
+}
+
+// SetLanguage makes lang the language of the response.
+func SetLanguage(c *fiber.Ctx, lang string) {
+	c.Locals(localsKey, lang)
+	c.Set(fiber.HeaderContentLanguage, lang)
+}
+
+// Language returns the language of the response.
+func Language(c *fiber.Ctx) string {
+	if lang, ok := c.Locals(localsKey).(string); ok {
+		return lang
+	}
+	return Default
+}
+
+// Message returns the success message under key in the language of the
+// response.
+func Message(c *fiber.Ctx, key, fallback string) string {
+	return T(Language(c), "message."+key, nil, fallback)
+}
+
+// FromAcceptLanguage returns the supported language the header prefers most,
+// or "" if it names none.
+func FromAcceptLanguage(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		// "in" is the old code for Indonesian that some systems still send.
+		if lang == "in" {
+			lang = Indonesian
+		}
+		if quality > 0 && Supported(lang) {
+			candidates = append(candidates, candidate{lang, quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
@@ -0,0 +1,264 @@
+// Package jwtkeys holds the keys that sign and verify access tokens. Tokens
+// are signed with a private key, EdDSA (Ed25519) or RS256, and name it in
+// their kid header, so that other services can verify them with the public
+// keys published as a JWK set without being able to mint tokens themselves.
+//
+// A key set has one signing key and any number of keys that only verify.
+// Rotating keys means adding a new key, signing with it, and removing the old
+// one once the tokens it signed have expired.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algorithms keys can be used with.
+const (
+	EdDSA = "EdDSA"
+	RS256 = "RS256"
+)
+
+// minRSABits is the size below which an RSA key is refused.
+const minRSABits = 2048
+
+// Key is one key of a set. Private is nil for a key that only verifies.
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+}
+
+// KeySet is the keys of the service: one to sign tokens with, all to verify
+// them.
+type KeySet struct {
+	signing *Key
+	// keys are all the keys, sorted by ID, the signing key included.
+	keys []*Key
+}
+
+// New returns a key set that signs with the key named signingID and verifies
+// with all of keys.
+func New(signingID string, keys ...*Key) (*KeySet, error) {
+	set := &KeySet{keys: keys}
+	sort.Slice(set.keys, func(i, j int) bool { return set.keys[i].ID < set.keys[j].ID })
+	for i, key := range set.keys {
+		if i > 0 && set.keys[i-1].ID == key.ID {
+			return nil, fmt.Errorf("two keys have the ID %q", key.ID)
+		}
+		if key.ID == signingID {
+			set.signing = key
+		}
+	}
+	if set.signing == nil {
+		return nil, fmt.Errorf("there is no key with the ID %q to sign with", signingID)
+	}
+	if set.signing.Private == nil {
+		return nil, fmt.Errorf("key %q cannot sign: it is a public key", signingID)
+	}
+	return set, nil
+}
+
+// LoadDir reads every .pem file of dir as a key whose ID is the name of the
+// file without .pem. Files may hold a private key, PKCS#8 or PKCS#1, or a
+// public key. signingID picks the key to sign with; it may be empty if
+// exactly one of the keys is private.
+func LoadDir(dir, signingID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .pem key files in %s", dir)
+	}
+
+	var keys []*Key
+	var private []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParsePEM(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if key.Private != nil {
+			private = append(private, key.ID)
+		}
+		keys = append(keys, key)
+	}
+
+	if signingID == "" {
+		if len(private) != 1 {
+			return nil, fmt.Errorf("%s has %d private keys, the signing key ID must say which one signs", dir, len(private))
+		}
+		signingID = private[0]
+	}
+	return New(signingID, keys...)
+}
+
+// ParsePEM parses the first PEM block of data as a key with the given ID.
+func ParsePEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{ID: id}
+	switch k := parsed.(type) {
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private, key.Public = EdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Algorithm, key.Public = EdDSA, k
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private, key.Public = RS256, k, k.Public()
+	case *rsa.PublicKey:
+		key.Algorithm, key.Public = RS256, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use Ed25519 or RSA", parsed)
+	}
+	if rsaKey, ok := key.Public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA key has %d bits, at least %d are needed", rsaKey.N.BitLen(), minRSABits)
+	}
+	return key, nil
+}
+
+// Generate returns a new private key for algorithm, EdDSA or RS256, encoded
+// as PKCS#8 PEM.
+func Generate(algorithm string) ([]byte, error) {
+	var private interface{}
+	var err error
+	switch algorithm {
+	case EdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case RS256:
+		private, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q, use %s or %s", algorithm, EdDSA, RS256)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// Ephemeral returns a key set with a new Ed25519 key that lives only as long
+// as the process, for a development server that was given no keys.
+func Ephemeral() (*KeySet, error) {
+	data, err := Generate(EdDSA)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	key, err := ParsePEM("ephemeral-"+hex.EncodeToString(id), data)
+	if err != nil {
+		return nil, err
+	}
+	return New(key.ID, key)
+}
+
+// SigningKeyID returns the ID of the key tokens are signed with.
+func (s *KeySet) SigningKeyID() string {
+	return s.signing.ID
+}
+
+// Sign returns a token with claims, signed with the signing key.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(s.signing.Algorithm), claims)
+	token.Header["kid"] = s.signing.ID
+	return token.SignedString(s.signing.Private)
+}
+
+// Keyfunc returns the key to verify token with: the key named by its kid
+// header, if the token was signed with the algorithm of that key.
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+	for _, key := range s.keys {
+		if key.ID != id {
+			continue
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("key %q is not for %s", id, token.Method.Alg())
+		}
+		return key.Public, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", id)
+}
+
+// JWK is a public key in the JSON Web Key format of RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty" example:"OKP"`
+	Use       string `json:"use" example:"sig"`
+	ID        string `json:"kid" example:"2026-10"`
+	Algorithm string `json:"alg" example:"EdDSA"`
+	// Curve and X are set for Ed25519 keys.
+	Curve string `json:"crv,omitempty" example:"Ed25519"`
+	X     string `json:"x,omitempty"`
+	// N and E are set for RSA keys.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set, every one that verifies tokens.
+func (s *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(s.keys))}
+	for _, key := range s.keys {
+		jwk := JWK{Use: "sig", ID: key.ID, Algorithm: key.Algorithm}
+		switch public := key.Public.(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType, jwk.Curve = "OKP", "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
@@ -0,0 +1,54 @@
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+// EmptyDigest is the digest of an election without ballots.
+const EmptyDigest = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// digestModulus keeps digests to the 256 bits of a SHA-256 hash.
+var digestModulus = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// HashBallot returns the hex SHA-256 of a ballot as it is stored while the
+// election is open.
+func HashBallot(electionID int, ballotID string, candidateID int, castDate string) string {
+	data := fmt.Sprintf("ballot|%d|%s|%d|%s", electionID, ballotID, candidateID, castDate)
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddToDigest returns digest with the ballots of the given hashes added.
+//
+// A digest is the sum of the hashes of a set of ballots modulo 2^256. Unlike
+// a hash chain it does not depend on the order the ballots were added in, so
+// it can be kept up to date as ballots are cast and compared with the stored
+// ballots at close without recording the order they were cast in.
+func AddToDigest(digest string, hashes ...string) (string, error) {
+	sum, err := parseDigest(digest)
+	if err != nil {
+		return "", err
+	}
+	for _, h := range hashes {
+		n, err := parseDigest(h)
+		if err != nil {
+			return "", err
+		}
+		sum.Add(sum, n)
+	}
+	sum.Mod(sum, digestModulus)
+
+	b := make([]byte, 32)
+	return hex.EncodeToString(sum.FillBytes(b)), nil
+}
+
+func parseDigest(s string) (*big.Int, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		return nil, fmt.Errorf("invalid digest %q", s)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,176 @@
+// Package ledger implements the hash-chained ballot ledger built for every
+// election when it closes, and the Merkle tree published over it.
+// It has no dependencies on the rest of the application so that the offline
+// verifier can use exactly the same rules as the server.
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// GenesisHash is the previous hash of the first entry of every ledger.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+type Entry struct {
+	ElectionID  int    `json:"election_id"`
+	Seq         int    `json:"seq"`
+	BallotID    string `json:"ballot_id"`
+	CandidateID int    `json:"candidate_id"`
+	CastDate    string `json:"cast_date"`
+	PrevHash    string `json:"prev_hash"`
+	Hash        string `json:"hash"`
+}
+
+type ProofStep struct {
+	Hash     string `json:"hash"`
+	Position string `json:"position"`
+}
+
+// NewEntry builds the entry that follows prevHash and computes its hash.
+func NewEntry(electionID, seq int, ballotID string, candidateID int, castDate, prevHash string) Entry {
+	e := Entry{
+		ElectionID:  electionID,
+		Seq:         seq,
+		BallotID:    ballotID,
+		CandidateID: candidateID,
+		CastDate:    castDate,
+		PrevHash:    prevHash,
+	}
+	e.Hash = HashEntry(e)
+	return e
+}
+
+// HashEntry returns the hex SHA-256 of the entry's fields and the hash of the
+// entry before it.
+func HashEntry(e Entry) string {
+	data := fmt.Sprintf("%d|%d|%s|%d|%s|%s", e.ElectionID, e.Seq, e.BallotID, e.CandidateID, e.CastDate, e.PrevHash)
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyChain replays the ledger of one election and reports the first entry
+// that is out of sequence, not linked to the entry before it, or whose hash
+// does not match its contents.
+func VerifyChain(entries []Entry) error {
+	prev := GenesisHash
+	for i, e := range entries {
+		if e.Seq != i+1 {
+			return fmt.Errorf("entry %d: expected seq %d, got %d", i+1, i+1, e.Seq)
+		}
+		if e.PrevHash != prev {
+			return fmt.Errorf("entry %d: previous hash does not match the entry before it", e.Seq)
+		}
+		if HashEntry(e) != e.Hash {
+			return fmt.Errorf("entry %d: hash does not match its contents", e.Seq)
+		}
+		prev = e.Hash
+	}
+	return nil
+}
+
+// MerkleRoot returns the root of the Merkle tree whose leaves are the given
+// entry hashes. Leaves and inner nodes are hashed with different prefixes, and
+// an odd node at the end of a level is carried up unchanged.
+func MerkleRoot(hashes []string) (string, error) {
+	if len(hashes) == 0 {
+		sum := sha256.Sum256(nil)
+		return hex.EncodeToString(sum[:]), nil
+	}
+
+	level, err := leaves(hashes)
+	if err != nil {
+		return "", err
+	}
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+	return hex.EncodeToString(level[0]), nil
+}
+
+// Proof returns the sibling hashes needed to rebuild the Merkle root from the
+// leaf at index.
+func Proof(hashes []string, index int) ([]ProofStep, error) {
+	if index < 0 || index >= len(hashes) {
+		return nil, errors.New("leaf index out of range")
+	}
+
+	level, err := leaves(hashes)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]ProofStep, 0)
+	for len(level) > 1 {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			position := "right"
+			if sibling < index {
+				position = "left"
+			}
+			steps = append(steps, ProofStep{Hash: hex.EncodeToString(level[sibling]), Position: position})
+		}
+		level = nextLevel(level)
+		index /= 2
+	}
+	return steps, nil
+}
+
+// VerifyProof reports whether the proof links the entry hash to the root.
+func VerifyProof(entryHash string, steps []ProofStep, root string) bool {
+	node, err := leaves([]string{entryHash})
+	if err != nil {
+		return false
+	}
+	current := node[0]
+	for _, step := range steps {
+		sibling, err := hex.DecodeString(step.Hash)
+		if err != nil {
+			return false
+		}
+		switch step.Position {
+		case "left":
+			current = hashNode(sibling, current)
+		case "right":
+			current = hashNode(current, sibling)
+		default:
+			return false
+		}
+	}
+	return hex.EncodeToString(current) == root
+}
+
+func leaves(hashes []string) ([][]byte, error) {
+	level := make([][]byte, len(hashes))
+	for i, h := range hashes {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry hash %q", h)
+		}
+		sum := sha256.Sum256(append([]byte{0x00}, b...))
+		level[i] = sum[:]
+	}
+	return level, nil
+}
+
+func nextLevel(level [][]byte) [][]byte {
+	next := make([][]byte, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+			continue
+		}
+		next = append(next, hashNode(level[i], level[i+1]))
+	}
+	return next
+}
+
+func hashNode(left, right []byte) []byte {
+	data := make([]byte, 0, 1+len(left)+len(right))
+	data = append(data, 0x01)
+	data = append(data, left...)
+	data = append(data, right...)
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
@@ -0,0 +1,167 @@
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// leafHashes returns n distinct entry hashes.
+func leafHashes(n int) []string {
+	hashes := make([]string, n)
+	for i := range hashes {
+		sum := sha256.Sum256([]byte(fmt.Sprint("entry ", i)))
+		hashes[i] = hex.EncodeToString(sum[:])
+	}
+	return hashes
+}
+
+func TestProofEveryLeaf(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		hashes := leafHashes(n)
+		root, err := MerkleRoot(hashes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, h := range hashes {
+			steps, err := Proof(hashes, i)
+			if err != nil {
+				t.Fatalf("%d leaves, leaf %d: %v", n, i, err)
+			}
+			if !VerifyProof(h, steps, root) {
+				t.Errorf("%d leaves, leaf %d: proof does not verify", n, i)
+			}
+			if other := hashes[(i+1)%n]; n > 1 && VerifyProof(other, steps, root) {
+				t.Errorf("%d leaves, leaf %d: proof verifies leaf %d too", n, i, (i+1)%n)
+			}
+		}
+	}
+}
+
+// TestOddLeafCarriedUp checks that the last node of a level with an odd
+// number of nodes is carried up unchanged rather than paired with itself.
+func TestOddLeafCarriedUp(t *testing.T) {
+	hashes := leafHashes(3)
+	level, err := leaves(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hex.EncodeToString(hashNode(hashNode(level[0], level[1]), level[2]))
+
+	root, err := MerkleRoot(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != want {
+		t.Fatalf("got root %s, want %s", root, want)
+	}
+
+	steps, err := Proof(hashes, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 1 || steps[0].Position != "left" {
+		t.Fatalf("got proof %+v for the odd leaf, want one step on the left", steps)
+	}
+}
+
+func TestProofOutOfRange(t *testing.T) {
+	hashes := leafHashes(3)
+	for _, index := range []int{-1, 3} {
+		if _, err := Proof(hashes, index); err == nil {
+			t.Errorf("leaf %d of 3: got no error", index)
+		}
+	}
+}
+
+func TestVerifyProofRejectsTampering(t *testing.T) {
+	hashes := leafHashes(5)
+	root, err := MerkleRoot(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, err := Proof(hashes, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flipped := append([]ProofStep(nil), steps...)
+	flipped[0].Position = "right"
+	if VerifyProof(hashes[4], flipped, root) {
+		t.Error("proof with a flipped step verifies")
+	}
+	invalid := append([]ProofStep(nil), steps...)
+	invalid[0].Position = "middle"
+	if VerifyProof(hashes[4], invalid, root) {
+		t.Error("proof with an unknown position verifies")
+	}
+	if VerifyProof(hashes[4], steps[:len(steps)-1], root) {
+		t.Error("shortened proof verifies")
+	}
+}
+
+func TestVerifyChain(t *testing.T) {
+	entries := make([]Entry, 0, 3)
+	prev := GenesisHash
+	for i := 1; i <= 3; i++ {
+		e := NewEntry(1, i, fmt.Sprint("ballot-", i), i, "2025-06-13", prev)
+		entries = append(entries, e)
+		prev = e.Hash
+	}
+	if err := VerifyChain(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := append([]Entry(nil), entries...)
+	tampered[1].CandidateID = 9
+	if err := VerifyChain(tampered); err == nil {
+		t.Error("chain with a changed candidate verifies")
+	}
+	if err := VerifyChain([]Entry{entries[0], entries[2]}); err == nil {
+		t.Error("chain with a missing entry verifies")
+	}
+}
+
+func TestAddToDigest(t *testing.T) {
+	a := HashBallot(1, "a", 1, "2025-06-13")
+	b := HashBallot(1, "b", 2, "2025-06-13")
+	c := HashBallot(1, "c", 1, "2025-06-14")
+
+	all, err := AddToDigest(EmptyDigest, a, b, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, err := AddToDigest(EmptyDigest, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step, err = AddToDigest(step, b, a); err != nil {
+		t.Fatal(err)
+	}
+	if step != all {
+		t.Fatalf("got %s adding in batches, want %s", step, all)
+	}
+
+	if other, _ := AddToDigest(EmptyDigest, a, b, HashBallot(1, "c", 2, "2025-06-14")); other == all {
+		t.Fatal("digest does not change with the candidate of a ballot")
+	}
+	if fewer, _ := AddToDigest(EmptyDigest, a, b); fewer == all {
+		t.Fatal("digest does not change when a ballot is missing")
+	}
+}
+
+func TestAddToDigestWraps(t *testing.T) {
+	max := "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	one := "0000000000000000000000000000000000000000000000000000000000000001"
+	got, err := AddToDigest(max, one, one)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != one {
+		t.Fatalf("got %s, want %s", got, one)
+	}
+	if _, err := AddToDigest(EmptyDigest, "abc"); err == nil {
+		t.Fatal("got no error for a hash that is not 32 bytes of hex")
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Account is what protected routes need to know about the logged-in user.
+type Account struct {
+	// Permissions are those the role of the user grants.
+	Permissions []string
+	Language    string
+	Disabled    bool
+	// TwoFactorMissing is whether the user has to turn on two-factor
+	// authentication before anything RequireTwoFactor guards.
+	TwoFactorMissing bool
+}
+
+// ErrTwoFactorSetupRequired is returned to a user who has to turn on
+// two-factor authentication before they can do anything else.
+var ErrTwoFactorSetupRequired = apperror.Forbidden("two_factor_setup_required", "turn on two-factor authentication first, at /me/2fa")
+
+// LoadAccount reads the account of the logged-in user with lookup on every
+// request, so that disabling a user, changing their role or changing the
+// permissions of the role takes effect at once instead of when their token
+// expires. lookup returns nil for a user that does not exist. The user is
+// answered in the language they chose, if any. It must run after Protected.
+func LoadAccount(lookup func(userID int) (*Account, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := UserID(c)
+		if !ok {
+			return apperror.ErrUnauthorized
+		}
+		account, err := lookup(userID)
+		if err != nil {
+			return err
+		}
+		if account == nil {
+			return apperror.ErrUnauthorized
+		}
+		if account.Disabled {
+			return apperror.ErrAccountDisabled
+		}
+
+		c.Locals(permissionsKey, account.Permissions)
+		c.Locals(twoFactorMissingKey, account.TwoFactorMissing)
+		if i18n.Supported(account.Language) {
+			i18n.SetLanguage(c, account.Language)
+		}
+		return c.Next()
+	}
+}
+
+const twoFactorMissingKey = "two_factor_missing"
+
+// RequireTwoFactor lets the request through only if the user does not have
+// to turn on two-factor authentication first. It must run after LoadAccount.
+func RequireTwoFactor() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if missing, _ := c.Locals(twoFactorMissingKey).(bool); missing {
+			return ErrTwoFactorSetupRequired
+		}
+		return c.Next()
+	}
+}
@@ -1,47 +1,98 @@
 package middleware
 
 import (
-	"os"
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/jwtkeys"
+	"net/http"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func Protected() fiber.Handler {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "zf9i95p(x_@^72-j_tj&=&(j^j&_7ku2d^vdpiotmu#gb*&h(3"
-	}
+// ErrSessionEnded is returned for an access token whose session was ended by
+// logging out or revoked, though the token itself has not expired.
+var ErrSessionEnded = apperror.New(http.StatusUnauthorized, "session_ended", "session has ended, log in again")
 
+// Protected lets the request through only with an access token signed with
+// one of keys, of a session that sessionActive says has not ended.
+func Protected(keys *jwtkeys.KeySet, sessionActive func(sessionID string) (bool, error)) fiber.Handler {
 	return jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{Key: []byte(jwtSecret)},
+		KeyFunc: keys.Keyfunc,
+		SuccessHandler: func(c *fiber.Ctx) error {
+			sessionID, ok := SessionID(c)
+			if !ok {
+				return apperror.ErrUnauthorized
+			}
+			active, err := sessionActive(sessionID)
+			if err != nil {
+				return err
+			}
+			if !active {
+				return ErrSessionEnded
+			}
+			return c.Next()
+		},
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Unauthorized",
-			})
+			return apperror.ErrUnauthorized
 		},
 	})
 }
 
-func RequireRole(requiredRole string) fiber.Handler {
+// RequirePermission lets the request through only if the role of the user
+// grants permission. It must run after LoadAccount.
+func RequirePermission(permission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		user := c.Locals("user").(*jwt.Token)
-		claims := user.Claims.(jwt.MapClaims)
-
-		role, ok := claims["role"].(string)
-		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error": "Role not found in token",
-			})
+		if !HasPermission(c, permission) {
+			return apperror.ErrForbidden
 		}
+		return c.Next()
+	}
+}
 
-		if role != requiredRole {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error": "Forbidden: insufficient permissions",
-			})
-		}
+func UserID(c *fiber.Ctx) (int, bool) {
+	user, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return 0, false
+	}
+	claims := user.Claims.(jwt.MapClaims)
 
-		return c.Next()
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(userID), true
+}
+
+// SessionID returns the session of the access token, which tokens issued
+// before there were sessions do not name.
+func SessionID(c *fiber.Ctx) (string, bool) {
+	user, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return "", false
+	}
+	claims := user.Claims.(jwt.MapClaims)
+
+	sessionID, ok := claims["sid"].(string)
+	return sessionID, ok && sessionID != ""
+}
+
+// permissionsKey is where LoadAccount keeps the permissions the role of the
+// user grants now.
+const permissionsKey = "permissions"
+
+// Permissions returns the permissions of the logged-in user, none if
+// LoadAccount has not run.
+func Permissions(c *fiber.Ctx) []string {
+	permissions, _ := c.Locals(permissionsKey).([]string)
+	return permissions
+}
+
+func HasPermission(c *fiber.Ctx, permission string) bool {
+	for _, p := range Permissions(c) {
+		if p == permission {
+			return true
+		}
 	}
+	return false
 }
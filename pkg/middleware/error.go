@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"errors"
+	"legiskuy-backend/pkg/apperror"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/i18n"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrorHandler writes every error a handler returns as an apperror.Response,
+// in the language of the request.
+// Errors that are not an *apperror.Error are not meant for the client: they
+// are logged with the request ID and answered with a plain internal error.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+
+	var appErr *apperror.Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &appErr):
+		if appErr.Status >= fiber.StatusInternalServerError {
+			log.Printf("Gagal memproses permintaan %s %s [%s]: %v", c.Method(), c.Path(), requestID, err)
+		}
+	case errors.As(err, &fiberErr):
+		appErr = apperror.New(fiberErr.Code, codeForStatus(fiberErr.Code), fiberErr.Message).
+			WithParams(map[string]string{"method": c.Method(), "path": c.Path()})
+		err = appErr
+	case database.IsUniqueViolation(err):
+		// A service that did not expect the violation, so the message
+		// cannot say more than this.
+		appErr = apperror.Conflict(apperror.CodeConflict, "Resource already exists")
+		err = appErr
+	default:
+		log.Printf("Gagal memproses permintaan %s %s [%s]: %v", c.Method(), c.Path(), requestID, err)
+		appErr = apperror.ErrInternal
+		err = appErr
+	}
+
+	message, details := i18n.Error(i18n.Language(c), appErr)
+	if err != error(appErr) {
+		// Context wrapped around the error is not translated, so the
+		// English message is kept whole.
+		message = err.Error()
+	}
+
+	return c.Status(appErr.Status).JSON(apperror.Response{
+		Error:     message,
+		Code:      appErr.Code,
+		Details:   details,
+		RequestID: requestID,
+	})
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return "bad_request"
+	case fiber.StatusUnauthorized:
+		return apperror.CodeUnauthorized
+	case fiber.StatusForbidden:
+		return apperror.CodeForbidden
+	case fiber.StatusNotFound:
+		return apperror.CodeNotFound
+	case fiber.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case fiber.StatusConflict:
+		return apperror.CodeConflict
+	case fiber.StatusRequestEntityTooLarge:
+		return "request_too_large"
+	}
+	if status >= fiber.StatusInternalServerError {
+		return apperror.CodeInternal
+	}
+	return "bad_request"
+}
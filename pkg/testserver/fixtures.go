@@ -0,0 +1,127 @@
+package testserver
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Password is the password of the users Staff and RegisterVoter make.
+const Password = "rahasia123"
+
+// Election is what OpenElection sets up.
+type Election struct {
+	ID          int
+	DistrictID  int
+	PartyID     int
+	CandidateID int
+}
+
+// Expect fails the test unless resp has the status want, and closes its body.
+func (s *Server) Expect(resp *http.Response, want int) {
+	s.tb.Helper()
+	resp.Body.Close()
+	if resp.StatusCode != want {
+		s.tb.Fatalf("%s %s: got %d, want %d", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, want)
+	}
+}
+
+// Create posts body to path, expects 201 Created and decodes the answer into
+// v.
+func (s *Server) Create(token, path string, body, v interface{}) {
+	s.tb.Helper()
+	resp := s.Request(http.MethodPost, path, token, body)
+	if resp.StatusCode != http.StatusCreated {
+		resp.Body.Close()
+		s.tb.Fatalf("POST %s: got %d, want %d", path, resp.StatusCode, http.StatusCreated)
+	}
+	s.Decode(resp, v)
+}
+
+// Staff adds a user with the role and returns their token.
+func (s *Server) Staff(username, role string) string {
+	s.tb.Helper()
+	s.CreateUser(username, Password, role)
+	return s.Login(username, Password)
+}
+
+// OpenElection sets up an open election with one candidate in one district,
+// as the petugas whose token is given.
+func (s *Server) OpenElection(token string) Election {
+	s.tb.Helper()
+
+	var district, party, election, candidate struct {
+		ID int `json:"id"`
+	}
+	s.Create(token, "/api/v1/districts", map[string]interface{}{"name": "Dapil 1", "seats": 2}, &district)
+	s.Create(token, "/api/v1/parties", map[string]interface{}{"name": "Partai Satu", "abbreviation": "PS", "ballot_number": 1}, &party)
+	now := time.Now().UTC()
+	s.Create(token, "/api/v1/elections", map[string]interface{}{
+		"name":       "Pemilu",
+		"start_time": now.Add(-time.Hour).Format(time.RFC3339),
+		"end_time":   now.Add(time.Hour).Format(time.RFC3339),
+	}, &election)
+	s.Create(token, "/api/v1/candidates", map[string]interface{}{
+		"name":        "Calon",
+		"election_id": election.ID,
+		"party_id":    party.ID,
+		"district_id": district.ID,
+	}, &candidate)
+
+	s.Transition(token, election.ID, "scheduled")
+	s.Transition(token, election.ID, "open")
+	return Election{ID: election.ID, DistrictID: district.ID, PartyID: party.ID, CandidateID: candidate.ID}
+}
+
+// Transition moves the election to status.
+func (s *Server) Transition(token string, electionID int, status string) {
+	s.tb.Helper()
+	path := fmt.Sprintf("/api/v1/elections/%d/transitions", electionID)
+	s.Expect(s.Request(http.MethodPost, path, token, map[string]string{"status": status}), http.StatusOK)
+}
+
+// RegisterVoter registers a voter, who the petugas whose token is given
+// assigns to the district, and returns the token of the voter.
+func (s *Server) RegisterVoter(token, username string, districtID int) string {
+	s.tb.Helper()
+	var user struct {
+		ID int `json:"id"`
+	}
+	s.Create("", "/api/v1/register", map[string]string{
+		"name":     username,
+		"username": username,
+		"password": Password,
+	}, &user)
+
+	var voters []struct {
+		ID     int `json:"id"`
+		UserID int `json:"user_id"`
+	}
+	s.Decode(s.Request(http.MethodGet, "/api/v1/voters?name="+username, token, nil), &voters)
+	for _, v := range voters {
+		if v.UserID != user.ID {
+			continue
+		}
+		path := fmt.Sprintf("/api/v1/voters/%d", v.ID)
+		s.Expect(s.Request(http.MethodPut, path, token, map[string]interface{}{"name": username, "district_id": districtID}), http.StatusOK)
+		return s.Login(username, Password)
+	}
+	s.tb.Fatalf("no voter found for %s", username)
+	return ""
+}
+
+// CastVote votes for the candidate as the voter whose token is given and
+// returns the receipt code.
+func (s *Server) CastVote(voterToken string, candidateID int) string {
+	s.tb.Helper()
+	resp := s.Request(http.MethodPost, "/api/v1/votes", voterToken, map[string]int{"candidate_id": candidateID})
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		s.tb.Fatalf("POST /api/v1/votes: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var vote struct {
+		Receipt string `json:"receipt"`
+	}
+	s.Decode(resp, &vote)
+	return vote.Receipt
+}
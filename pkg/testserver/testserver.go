@@ -0,0 +1,242 @@
+// Package testserver runs the whole application on a private database for
+// integration tests:
+//
+//	srv := testserver.New(t)
+//	srv.CreateUser("admin", "rahasia", "petugas")
+//	token := srv.Login("admin", "rahasia")
+//	resp := srv.Request(http.MethodPost, "/api/v1/districts", token, body)
+//
+// New uses SQLite. NewPostgres uses a schema of its own in the PostgreSQL
+// database named by TEST_DATABASE_URL, and skips the test when it is not set.
+// Run runs a test against both.
+//
+// Every Server has its own database, so tests using it may run in parallel.
+package testserver
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"legiskuy-backend/internal/app"
+	"legiskuy-backend/internal/auth"
+	"legiskuy-backend/pkg/database"
+	"legiskuy-backend/pkg/jwtkeys"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Server struct {
+	App *fiber.App
+	DB  *database.Database
+
+	tb testing.TB
+	// mu serializes requests. The database allows one writer at a time, and
+	// requests that wait on each other inside SQLite make tests slow and
+	// their failures hard to read.
+	mu sync.Mutex
+}
+
+// PostgresURLEnv is the environment variable NewPostgres reads the URL of its
+// PostgreSQL database from.
+const PostgresURLEnv = "TEST_DATABASE_URL"
+
+// Run runs test as two subtests, one on a server from New and one on a server
+// from NewPostgres.
+func Run(t *testing.T, test func(t *testing.T, srv *Server)) {
+	t.Helper()
+	t.Run("sqlite", func(t *testing.T) { test(t, New(t)) })
+	t.Run("postgres", func(t *testing.T) { test(t, NewPostgres(t)) })
+}
+
+// New starts a server on a fresh, fully migrated SQLite database. Both are
+// closed when the test ends.
+func New(tb testing.TB) *Server {
+	tb.Helper()
+
+	// The database is a file rather than a shared in-memory database, whose
+	// writers fail instead of waiting for each other, so that tests can send
+	// requests concurrently.
+	db, err := database.Connect("sqlite", filepath.Join(tb.TempDir(), "legiskuy.db"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	return start(tb, db)
+}
+
+// NewPostgres starts a server on a fresh, fully migrated schema of the
+// PostgreSQL database named by TEST_DATABASE_URL, and skips the test when the
+// variable is not set. The schema is dropped when the test ends.
+func NewPostgres(tb testing.TB) *Server {
+	tb.Helper()
+
+	url := os.Getenv(PostgresURLEnv)
+	if url == "" {
+		tb.Skipf("%s is not set", PostgresURLEnv)
+	}
+
+	admin, err := database.Connect("postgres", url)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { admin.Close() })
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		tb.Fatal(err)
+	}
+	schema := "test_" + hex.EncodeToString(b)
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			tb.Error(err)
+		}
+	})
+
+	db, err := database.Connect("postgres", withSearchPath(url, schema))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	return start(tb, db)
+}
+
+// withSearchPath adds the search_path parameter to url, which lib/pq takes
+// either as a URL or as key=value pairs.
+func withSearchPath(url, schema string) string {
+	if !strings.HasPrefix(url, "postgres://") && !strings.HasPrefix(url, "postgresql://") {
+		return url + " search_path=" + schema
+	}
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+	return url + separator + "search_path=" + schema
+}
+
+// start migrates db and starts the application on it.
+func start(tb testing.TB, db *database.Database) *Server {
+	tb.Helper()
+
+	if _, err := database.Migrate(db, 0); err != nil {
+		tb.Fatal(err)
+	}
+
+	keys, err := jwtkeys.Ephemeral()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return &Server{
+		// Requests are sent one after the other, so ballots would wait
+		// for others in vain.
+		App: app.New(app.Config{DB: db, Keys: keys, BallotDelay: time.Millisecond}),
+		DB:  db,
+		tb:  tb,
+	}
+}
+
+// Do sends req to the app and returns its response.
+func (s *Server) Do(req *http.Request) *http.Response {
+	s.tb.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp, err := s.App.Test(req, -1)
+	if err != nil {
+		s.tb.Fatal(err)
+	}
+	return resp
+}
+
+// Request sends body, encoded as JSON unless it is nil, to path. The token is
+// sent as a bearer token when it is not empty.
+func (s *Server) Request(method, path, token string, body interface{}) *http.Response {
+	s.tb.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			s.tb.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return s.Do(req)
+}
+
+// Decode reads the JSON body of resp into v and closes it.
+func (s *Server) Decode(resp *http.Response, v interface{}) {
+	s.tb.Helper()
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		s.tb.Fatal(err)
+	}
+}
+
+// CreateUser adds a user directly to the database, which is how a test gets
+// its first petugas.
+func (s *Server) CreateUser(username, password, role string) *auth.User {
+	s.tb.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		s.tb.Fatal(err)
+	}
+
+	repo := auth.NewRepository(s.DB)
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		s.tb.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	user, err := repo.Create(tx, &auth.User{Name: username, Username: username, Password: string(hash), Role: role})
+	if err != nil {
+		s.tb.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		s.tb.Fatal(err)
+	}
+	return user
+}
+
+// Login logs the user in through the API and returns their token.
+func (s *Server) Login(username, password string) string {
+	s.tb.Helper()
+
+	resp := s.Request(http.MethodPost, "/api/v1/login", "", map[string]string{
+		"username": username,
+		"password": password,
+	})
+	var body struct {
+		Token string `json:"token"`
+		Error string `json:"error"`
+	}
+	s.Decode(resp, &body)
+	if resp.StatusCode != http.StatusOK {
+		s.tb.Fatalf("login as %s failed with %d: %s", username, resp.StatusCode, body.Error)
+	}
+	return body.Token
+}
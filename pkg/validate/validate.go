@@ -0,0 +1,270 @@
+// Package validate checks the input of a request against the rules in the
+// validate tags of its struct:
+//
+//	type CreateVoterInput struct {
+//		Name string `json:"name" validate:"trim,required,max=100"`
+//	}
+//
+// Rules are applied in the order they are listed, and a field fails on the
+// first rule it breaks. Every field is checked, and all that fail are
+// reported at once as one apperror.Fields error, named as they are in JSON.
+//
+// The rules are:
+//
+//	trim         removes the whitespace around a string, in the input itself
+//	required     the value is not "", zero or nil
+//	min=N        a string has at least N characters
+//	max=N        a string has at most N characters
+//	positive     a number is greater than zero
+//	nonnegative  a number is not below zero
+//	between=A B  a number is from A to B
+//	oneof=A B C  a string is one of the words listed
+//	rfc3339      a string is a time such as 2025-06-13T00:00:00Z
+//	hexcolor     a string is a color such as #FF0000
+//	username     a string is fit to be a username
+//	password     a string is a password strong enough to keep
+//
+// Rules other than trim and required pass empty strings and nil pointers,
+// so a field that may be left out only needs required left out of its tag.
+package validate
+
+import (
+	"legiskuy-backend/pkg/apperror"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// failure is a rule a value breaks. Its message has the placeholder {field}
+// and those of params.
+type failure struct {
+	code    string
+	message string
+	params  map[string]string
+}
+
+// rule checks value, which is never a pointer, against the argument written
+// after = in the tag.
+type rule func(value reflect.Value, arg string) *failure
+
+var rules = map[string]rule{
+	"min":         minLength,
+	"max":         maxLength,
+	"positive":    positive,
+	"nonnegative": nonnegative,
+	"between":     between,
+	"oneof":       oneOf,
+	"rfc3339":     rfc3339,
+	"hexcolor":    hexColor,
+	"username":    username,
+	"password":    password,
+}
+
+// Struct checks the fields of the struct v points to and returns an
+// *apperror.Error listing every field that breaks its rules, or nil.
+func Struct(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		panic("validate: Struct needs a pointer to a struct, not " + value.Type().String())
+	}
+	value = value.Elem()
+
+	var fields []apperror.FieldError
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		tag, ok := field.Tag.Lookup("validate")
+		if !ok {
+			continue
+		}
+		name := jsonName(field)
+		if f := checkField(value.Field(i), strings.Split(tag, ",")); f != nil {
+			params := map[string]string{"field": name}
+			for key, param := range f.params {
+				params[key] = param
+			}
+			fields = append(fields, apperror.FieldError{
+				Field:   name,
+				Code:    f.code,
+				Message: apperror.Format(f.message, params),
+				Params:  f.params,
+			})
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return apperror.Fields(fields...)
+}
+
+func checkField(value reflect.Value, tagRules []string) *failure {
+	for _, tagRule := range tagRules {
+		name, arg, _ := strings.Cut(tagRule, "=")
+		switch name {
+		case "trim":
+			if value.Kind() == reflect.String {
+				value.SetString(strings.TrimSpace(value.String()))
+			}
+			continue
+		case "required":
+			if value.IsZero() {
+				return &failure{code: "required", message: "{field} is required"}
+			}
+			continue
+		}
+
+		check, ok := rules[name]
+		if !ok {
+			panic("validate: unknown rule " + name)
+		}
+		if isEmpty(value) {
+			return nil
+		}
+		if f := check(reflect.Indirect(value), arg); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer:
+		return value.IsNil()
+	case reflect.String:
+		return value.String() == ""
+	}
+	return false
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func number(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	}
+	panic("validate: " + value.Type().String() + " is not a number")
+}
+
+func minLength(value reflect.Value, arg string) *failure {
+	if utf8.RuneCountInString(value.String()) < mustAtoi(arg) {
+		return &failure{code: "too_short", message: "{field} must be at least {min} characters long", params: map[string]string{"min": arg}}
+	}
+	return nil
+}
+
+func maxLength(value reflect.Value, arg string) *failure {
+	if utf8.RuneCountInString(value.String()) > mustAtoi(arg) {
+		return &failure{code: "too_long", message: "{field} must be at most {max} characters long", params: map[string]string{"max": arg}}
+	}
+	return nil
+}
+
+func positive(value reflect.Value, _ string) *failure {
+	if number(value) <= 0 {
+		return &failure{code: "must_be_positive", message: "{field} must be greater than zero"}
+	}
+	return nil
+}
+
+func nonnegative(value reflect.Value, _ string) *failure {
+	if number(value) < 0 {
+		return &failure{code: "must_not_be_negative", message: "{field} must be a non-negative number"}
+	}
+	return nil
+}
+
+func between(value reflect.Value, arg string) *failure {
+	low, high, ok := strings.Cut(arg, " ")
+	if !ok {
+		panic("validate: between needs two numbers, not " + arg)
+	}
+	n := number(value)
+	if n < mustParseFloat(low) || n > mustParseFloat(high) {
+		return &failure{code: "out_of_range", message: "{field} must be between {min} and {max}", params: map[string]string{"min": low, "max": high}}
+	}
+	return nil
+}
+
+func oneOf(value reflect.Value, arg string) *failure {
+	choices := strings.Fields(arg)
+	for _, choice := range choices {
+		if value.String() == choice {
+			return nil
+		}
+	}
+	return &failure{code: "invalid_choice", message: "{field} must be one of: {choices}", params: map[string]string{"choices": strings.Join(choices, ", ")}}
+}
+
+func rfc3339(value reflect.Value, _ string) *failure {
+	if _, err := time.Parse(time.RFC3339, value.String()); err != nil {
+		return &failure{code: "invalid_format", message: "{field} must be an RFC3339 time such as 2025-06-13T00:00:00Z"}
+	}
+	return nil
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+func hexColor(value reflect.Value, _ string) *failure {
+	if !hexColorPattern.MatchString(value.String()) {
+		return &failure{code: "invalid_format", message: "{field} must be a hex color such as #FF0000"}
+	}
+	return nil
+}
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func username(value reflect.Value, _ string) *failure {
+	if !usernamePattern.MatchString(value.String()) {
+		return &failure{code: "invalid_format", message: "{field} must start with a letter or digit and contain only letters, digits, dots, underscores and hyphens"}
+	}
+	return nil
+}
+
+// maxPasswordBytes is the most bcrypt hashes. It refuses longer passwords
+// rather than ignore the rest of them.
+const maxPasswordBytes = 72
+
+func password(value reflect.Value, _ string) *failure {
+	s := value.String()
+	if len(s) > maxPasswordBytes {
+		return &failure{code: "too_long", message: "{field} must be at most {max} bytes long", params: map[string]string{"max": strconv.Itoa(maxPasswordBytes)}}
+	}
+	hasLetter := strings.IndexFunc(s, unicode.IsLetter) >= 0
+	hasDigit := strings.IndexFunc(s, unicode.IsDigit) >= 0
+	if !hasLetter || !hasDigit {
+		return &failure{code: "too_weak", message: "{field} must contain both letters and digits"}
+	}
+	return nil
+}
+
+func mustAtoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		panic("validate: " + s + " is not a number")
+	}
+	return n
+}
+
+func mustParseFloat(s string) float64 {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		panic("validate: " + s + " is not a number")
+	}
+	return n
+}
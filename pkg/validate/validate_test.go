@@ -0,0 +1,123 @@
+package validate
+
+import (
+	"errors"
+	"legiskuy-backend/pkg/apperror"
+	"strings"
+	"testing"
+)
+
+type input struct {
+	Name     string   `json:"name" validate:"trim,required,min=3,max=10"`
+	Username string   `json:"username" validate:"username"`
+	Password string   `json:"password" validate:"password"`
+	Seats    int      `json:"seats" validate:"positive"`
+	Votes    int      `json:"votes" validate:"nonnegative"`
+	Share    float64  `json:"share" validate:"between=0 100"`
+	Status   string   `json:"status" validate:"oneof=draft open"`
+	Start    *string  `json:"start_time" validate:"rfc3339"`
+	Color    string   `json:"color" validate:"hexcolor"`
+	Optional *float64 `json:"optional" validate:"positive"`
+	NoJSON   string   `validate:"max=1"`
+}
+
+// valid returns an input that passes every rule.
+func valid() input {
+	return input{Name: "Budi", Seats: 1, Share: 50, Status: "open", Color: "#FF0000", Username: "budi.s", Password: "rahasia123"}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(in *input)
+		field  string
+		code   string
+	}{
+		{"required", func(in *input) { in.Name = "" }, "name", "required"},
+		{"required after trim", func(in *input) { in.Name = "   " }, "name", "required"},
+		{"min", func(in *input) { in.Name = "Bu" }, "name", "too_short"},
+		{"min counts characters", func(in *input) { in.Name = "Ñañ" }, "", ""},
+		{"max", func(in *input) { in.Name = "Budi Santoso" }, "name", "too_long"},
+		{"username", func(in *input) { in.Username = ".budi" }, "username", "invalid_format"},
+		{"password without digits", func(in *input) { in.Password = "rahasia" }, "password", "too_weak"},
+		{"password too long", func(in *input) { in.Password = strings.Repeat("a1", 37) }, "password", "too_long"},
+		{"positive", func(in *input) { in.Seats = 0 }, "seats", "must_be_positive"},
+		{"nonnegative", func(in *input) { in.Votes = -1 }, "votes", "must_not_be_negative"},
+		{"between low", func(in *input) { in.Share = -0.5 }, "share", "out_of_range"},
+		{"between high", func(in *input) { in.Share = 100.5 }, "share", "out_of_range"},
+		{"between edge", func(in *input) { in.Share = 100 }, "", ""},
+		{"oneof", func(in *input) { in.Status = "closed" }, "status", "invalid_choice"},
+		{"rfc3339", func(in *input) { in.Start = ptr("2025-06-13 00:00") }, "start_time", "invalid_format"},
+		{"rfc3339 pointer", func(in *input) { in.Start = ptr("2025-06-13T00:00:00Z") }, "", ""},
+		{"hexcolor", func(in *input) { in.Color = "red" }, "color", "invalid_format"},
+		{"empty string skips rules", func(in *input) { in.Color = "" }, "", ""},
+		{"nil pointer skips rules", func(in *input) { in.Optional = nil }, "", ""},
+		{"pointer is checked", func(in *input) { in.Optional = ptr(-1.0) }, "optional", "must_be_positive"},
+		{"field without json name", func(in *input) { in.NoJSON = "ab" }, "NoJSON", "too_long"},
+	}
+	for _, tt := range tests {
+		in := valid()
+		tt.change(&in)
+		err := Struct(&in)
+		if tt.field == "" {
+			if err != nil {
+				t.Errorf("%s: got %v, want no error", tt.name, err)
+			}
+			continue
+		}
+
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) {
+			t.Fatalf("%s: got %v, want an *apperror.Error", tt.name, err)
+		}
+		if len(appErr.Fields) != 1 || appErr.Fields[0].Field != tt.field || appErr.Fields[0].Code != tt.code {
+			t.Errorf("%s: got %+v, want %s on %s", tt.name, appErr.Fields, tt.code, tt.field)
+		}
+	}
+}
+
+func TestStructTrims(t *testing.T) {
+	in := valid()
+	in.Name = "  Budi  "
+	if err := Struct(&in); err != nil {
+		t.Fatal(err)
+	}
+	if in.Name != "Budi" {
+		t.Fatalf("got name %q, want it trimmed", in.Name)
+	}
+}
+
+func TestStructReportsEveryField(t *testing.T) {
+	in := valid()
+	in.Name = ""
+	in.Seats = -1
+	in.Status = "closed"
+
+	var appErr *apperror.Error
+	if !errors.As(Struct(&in), &appErr) {
+		t.Fatal("got no *apperror.Error")
+	}
+	fields := make([]string, 0, len(appErr.Fields))
+	for _, d := range appErr.Fields {
+		fields = append(fields, d.Field)
+	}
+	if strings.Join(fields, ",") != "name,seats,status" {
+		t.Fatalf("got fields %v, want name, seats and status", fields)
+	}
+}
+
+func TestStructMessage(t *testing.T) {
+	in := valid()
+	in.Name = "Bu"
+
+	var appErr *apperror.Error
+	if !errors.As(Struct(&in), &appErr) {
+		t.Fatal("got no *apperror.Error")
+	}
+	d := appErr.Fields[0]
+	if d.Message != "name must be at least 3 characters long" || d.Params["min"] != "3" {
+		t.Fatalf("got message %q and params %v", d.Message, d.Params)
+	}
+}